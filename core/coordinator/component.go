@@ -3,7 +3,10 @@ package coordinator
 import (
 	"context"
 	"crypto/ed25519"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -11,13 +14,16 @@ import (
 	"github.com/pkg/errors"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/dig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/iotaledger/hive.go/core/app"
 	"github.com/iotaledger/hive.go/core/app/core/shutdown"
 	"github.com/iotaledger/hive.go/core/crypto"
 	"github.com/iotaledger/hive.go/core/events"
 	"github.com/iotaledger/hive.go/core/syncutils"
-	"github.com/iotaledger/hive.go/core/timeutil"
 	"github.com/iotaledger/hornet/v2/pkg/common"
 	"github.com/iotaledger/inx-app/nodebridge"
 	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
@@ -35,6 +41,10 @@ const (
 	CfgCoordinatorBootstrap = "cooBootstrap"
 	// CfgCoordinatorStartIndex defines the index of the first milestone at bootstrap.
 	CfgCoordinatorStartIndex = "cooStartIndex"
+	// CfgCoordinatorBootstrapPreviousMilestoneID defines the milestone ID referenced by the first milestone at bootstrap.
+	CfgCoordinatorBootstrapPreviousMilestoneID = "cooBootstrapPreviousMilestoneID"
+	// CfgCoordinatorVerifyChainStartIndex defines the index of the first milestone of the chain verification at startup.
+	CfgCoordinatorVerifyChainStartIndex = "cooVerifyChainStartIndex"
 	// MilestoneMaxAdditionalTipsLimit defines the maximum limit of additional tips that fit into a milestone (besides the last milestone and checkpoint hash).
 	MilestoneMaxAdditionalTipsLimit = 6
 )
@@ -60,12 +70,18 @@ var (
 	CoreComponent *app.CoreComponent
 	deps          dependencies
 
-	bootstrap  = flag.Bool(CfgCoordinatorBootstrap, false, "bootstrap the network")
-	startIndex = flag.Uint32(CfgCoordinatorStartIndex, 0, "index of the first milestone at bootstrap")
+	bootstrap             = flag.Bool(CfgCoordinatorBootstrap, false, "bootstrap the network")
+	startIndex            = flag.Uint32(CfgCoordinatorStartIndex, 0, "index of the first milestone at bootstrap")
+	verifyChainStartIndex = flag.Uint32(CfgCoordinatorVerifyChainStartIndex, 0, "index of the first milestone to verify the milestone chain at startup (0 = disabled)")
+
+	bootstrapPreviousMilestoneID = flag.String(CfgCoordinatorBootstrapPreviousMilestoneID, "", "the milestone ID referenced by the first milestone at bootstrap instead of the latest milestone of the node (hex, optional)")
 
 	nextCheckpointSignal chan struct{}
 	nextMilestoneSignal  chan struct{}
 
+	// the signing provider of the coordinator, which is closed at shutdown.
+	milestoneSignerProvider coordinator.MilestoneSignerProvider
+
 	heaviestSelectorLock syncutils.RWMutex
 
 	lastCheckpointIndex   int
@@ -76,7 +92,7 @@ var (
 	onBlockSolid                *events.Closure
 	onConfirmedMilestoneChanged *events.Closure
 	onIssuedCheckpoint          *events.Closure
-	onIssuedMilestone           *events.Closure
+	onStatePersistFailed        *events.Closure
 )
 
 type dependencies struct {
@@ -139,6 +155,12 @@ func provide(c *dig.Container) error {
 			if err != nil {
 				return nil, fmt.Errorf("failed to initialize signing provider: %w", err)
 			}
+			milestoneSignerProvider = signingProvider
+
+			checkpointStrategy, err := coordinator.ParseCheckpointStrategy(ParamsCoordinator.Checkpoints.Strategy)
+			if err != nil {
+				return nil, err
+			}
 
 			if ParamsCoordinator.Quorum.Enabled {
 				CoreComponent.LogInfo("running coordinator with quorum enabled")
@@ -148,6 +170,75 @@ func provide(c *dig.Container) error {
 				CoreComponent.LogInfo("running coordinator without migration enabled")
 			}
 
+			var milestoneExistsFunc coordinator.MilestoneExistsFunc
+			if ParamsCoordinator.CrashRecovery {
+				milestoneExistsFunc = func(index iotago.MilestoneIndex) (*coordinator.LatestMilestoneInfo, error) {
+					ms, err := deps.NodeBridge.Milestone(index)
+					if err != nil {
+						if status.Code(err) == codes.NotFound {
+							//nolint:nilnil // nil signals that the milestone does not exist
+							return nil, nil
+						}
+
+						return nil, err
+					}
+					if ms == nil {
+						//nolint:nilnil // nil signals that the milestone does not exist
+						return nil, nil
+					}
+
+					return &coordinator.LatestMilestoneInfo{
+						Index:       ms.Milestone.Index,
+						Timestamp:   ms.Milestone.Timestamp,
+						MilestoneID: ms.MilestoneID,
+					}, nil
+				}
+			}
+
+			cooOpts := []coordinator.Option{
+				coordinator.WithLogger(CoreComponent.Logger()),
+				coordinator.WithStateFilePath(ParamsCoordinator.StateFilePath),
+				coordinator.WithStateBackups(ParamsCoordinator.StateBackups),
+				coordinator.WithStateWriteRetry(ParamsCoordinator.StateWrite.RetryAmount, ParamsCoordinator.StateWrite.RetryBackoff),
+				coordinator.WithMilestoneInterval(ParamsCoordinator.Interval),
+				coordinator.WithIntervalJitter(ParamsCoordinator.IntervalJitter),
+				coordinator.WithMinMilestoneInterval(ParamsCoordinator.MinInterval),
+				coordinator.WithMerkleComputeTimeout(ParamsCoordinator.MerkleTimeout),
+				coordinator.WithQuorum(ParamsCoordinator.Quorum.Enabled, ParamsCoordinator.Quorum.Groups, ParamsCoordinator.Quorum.Timeout),
+				coordinator.WithQuorumCircuitBreaker(ParamsCoordinator.Quorum.CircuitBreaker.FailureThreshold, ParamsCoordinator.Quorum.CircuitBreaker.Cooldown),
+				coordinator.WithQuorumWeightThreshold(ParamsCoordinator.Quorum.WeightThreshold),
+				coordinator.WithQuorumConcurrencyLimit(ParamsCoordinator.Quorum.ConcurrencyLimit),
+				coordinator.WithQuorumFailClosed(ParamsCoordinator.Quorum.FailClosed),
+				coordinator.WithQuorumClockSkewThreshold(ParamsCoordinator.Quorum.ClockSkew),
+				coordinator.WithQuorumCompression(ParamsCoordinator.Quorum.Compression),
+				coordinator.WithQuorumHealthPoll(ParamsCoordinator.Quorum.HealthPoll),
+				coordinator.WithQuorumResultCache(ParamsCoordinator.Quorum.ResultCache.Size, ParamsCoordinator.Quorum.ResultCache.TTL),
+				coordinator.WithQuorumTransport(quorumTransport()),
+				coordinator.WithSigningRetryAmount(ParamsCoordinator.Signing.RetryAmount),
+				coordinator.WithSigningRetryTimeout(ParamsCoordinator.Signing.RetryTimeout),
+				coordinator.WithSigningMaxBackoff(ParamsCoordinator.Signing.MaxBackoff),
+				coordinator.WithSigningParallelism(ParamsCoordinator.Signing.Parallelism),
+				coordinator.WithForceMilestoneAfterCheckpoints(ParamsCoordinator.Checkpoints.ForceMilestoneAfter),
+				coordinator.WithMaxCheckpointsPerInterval(ParamsCoordinator.Checkpoints.MaxPerInterval),
+				coordinator.WithCheckpointStrategy(checkpointStrategy),
+				coordinator.WithCrashRecovery(milestoneExistsFunc),
+				coordinator.WithLatestMilestoneIndexFunc(deps.NodeBridge.LatestMilestoneIndex),
+			}
+
+			if *bootstrapPreviousMilestoneID != "" {
+				milestoneIDBytes, err := iotago.DecodeHex(*bootstrapPreviousMilestoneID)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s: %w", CfgCoordinatorBootstrapPreviousMilestoneID, err)
+				}
+				if len(milestoneIDBytes) != iotago.MilestoneIDLength {
+					return nil, fmt.Errorf("invalid %s: length must be %d bytes", CfgCoordinatorBootstrapPreviousMilestoneID, iotago.MilestoneIDLength)
+				}
+
+				milestoneID := iotago.MilestoneID{}
+				copy(milestoneID[:], milestoneIDBytes)
+				cooOpts = append(cooOpts, coordinator.WithBootstrapPreviousMilestoneID(milestoneID))
+			}
+
 			coo, err := coordinator.New(
 				ComputeMerkleTreeHash,
 				deps.NodeBridge.IsNodeSynced,
@@ -156,12 +247,7 @@ func provide(c *dig.Container) error {
 				deps.MigratorService,
 				treasuryListener.LatestTreasuryOutput,
 				sendBlock,
-				coordinator.WithLogger(CoreComponent.Logger()),
-				coordinator.WithStateFilePath(ParamsCoordinator.StateFilePath),
-				coordinator.WithMilestoneInterval(ParamsCoordinator.Interval),
-				coordinator.WithQuorum(ParamsCoordinator.Quorum.Enabled, ParamsCoordinator.Quorum.Groups, ParamsCoordinator.Quorum.Timeout),
-				coordinator.WithSigningRetryAmount(ParamsCoordinator.Signing.RetryAmount),
-				coordinator.WithSigningRetryTimeout(ParamsCoordinator.Signing.RetryTimeout),
+				cooOpts...,
 			)
 			if err != nil {
 				return nil, err
@@ -188,8 +274,26 @@ func provide(c *dig.Container) error {
 				return nil, err
 			}
 
+			if *verifyChainStartIndex != 0 {
+				CoreComponent.LogInfof("verifying milestone chain from %d ...", *verifyChainStartIndex)
+				if err := coo.VerifyChain(CoreComponent.Daemon().ContextStopped(), *verifyChainStartIndex, func(index iotago.MilestoneIndex) (*iotago.Milestone, error) {
+					ms, err := deps.NodeBridge.Milestone(index)
+					if err != nil {
+						return nil, err
+					}
+					if ms == nil {
+						return nil, fmt.Errorf("milestone %d not found", index)
+					}
+
+					return ms.Milestone, nil
+				}); err != nil {
+					return nil, fmt.Errorf("milestone chain verification failed: %w", err)
+				}
+				CoreComponent.LogInfo("verifying milestone chain ... done")
+			}
+
 			// don't issue milestones or checkpoints in case the node is running hot
-			coo.AddBackPressureFunc(todo.IsNodeTooLoaded)
+			coo.AddNamedBackPressureFunc("node load", todo.IsNodeTooLoaded)
 
 			return coo, nil
 		}
@@ -233,21 +337,34 @@ func configure() error {
 	return nil
 }
 
+// quorumTransport creates the transport shared by the quorum clients.
+func quorumTransport() *http.Transport {
+	//nolint:forcetypeassert // the default transport is always a *http.Transport
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = ParamsCoordinator.Quorum.Transport.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = ParamsCoordinator.Quorum.Transport.IdleConnTimeout
+
+	return transport
+}
+
 // handleError checks for critical errors and returns true if the node should shutdown.
 func handleError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	if err := common.IsCriticalError(err); err != nil {
+	if coordinator.IsCritical(err) {
 		deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("coordinator plugin hit a critical error: %s", err), true)
 
 		return true
 	}
 
-	if err := common.IsSoftError(err); err != nil {
-		CoreComponent.LogWarn(err)
-		deps.Coordinator.Events.SoftError.Trigger(err)
+	if coordinator.IsSoft(err) {
+		// soft errors the coordinator triggered the event for were already logged by the coordinator
+		if !coordinator.SoftErrorEventTriggered(err) {
+			CoreComponent.LogWarn(err)
+			deps.Coordinator.Events.SoftError.Trigger(err)
+		}
 
 		return false
 	}
@@ -263,15 +380,25 @@ func run() error {
 	// create a background worker that signals to issue new milestones
 	if err := CoreComponent.Daemon().BackgroundWorker("Coordinator[MilestoneTicker]", func(ctx context.Context) {
 		CoreComponent.LogInfo("Start MilestoneTicker")
-		ticker := timeutil.NewTicker(func() {
+		for {
+			// the interval can be changed at runtime, so it is read again for every milestone
+			timer := time.NewTimer(deps.Coordinator.Interval())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				CoreComponent.LogInfo("Stopped MilestoneTicker")
+
+				return
+			case <-timer.C:
+			}
+
 			// issue next milestone
 			select {
 			case nextMilestoneSignal <- struct{}{}:
 			default:
 				// do not block if already another signal is waiting
 			}
-		}, deps.Coordinator.Interval(), ctx)
-		ticker.WaitForGracefulShutdown()
+		}
 	}, daemon.PriorityStopCoordinatorMilestoneTicker); err != nil {
 		CoreComponent.LogPanicf("failed to start worker: %s", err)
 	}
@@ -294,6 +421,23 @@ func run() error {
 		}
 	}
 
+	if ParamsCoordinator.Webhook.URL != "" {
+		webhookNotifier := coordinator.NewWebhookNotifier(ParamsCoordinator.Webhook.URL,
+			coordinator.WithWebhookLogger(CoreComponent.Logger()),
+			coordinator.WithWebhookHeaders(ParamsCoordinator.Webhook.Headers),
+			coordinator.WithWebhookRetry(ParamsCoordinator.Webhook.RetryAmount, ParamsCoordinator.Webhook.RetryBackoff),
+		)
+		webhookNotifier.Attach(deps.Coordinator)
+
+		if err := CoreComponent.Daemon().BackgroundWorker("Coordinator[WebhookNotifier]", func(ctx context.Context) {
+			CoreComponent.LogInfo("Start WebhookNotifier")
+			webhookNotifier.Run(ctx)
+			CoreComponent.LogInfo("Stopped WebhookNotifier")
+		}, daemon.PriorityStopWebhookNotifier); err != nil {
+			CoreComponent.LogPanicf("failed to start worker: %s", err)
+		}
+	}
+
 	// create a background worker that issues milestones
 	if err := CoreComponent.Daemon().BackgroundWorker("Coordinator", func(ctx context.Context) {
 		attachEvents()
@@ -341,7 +485,8 @@ func run() error {
 					}
 
 					// issue a checkpoint
-					checkpointBlockID, err := deps.Coordinator.IssueCheckpoint(lastCheckpointIndex, lastCheckpointBlockID, tips)
+					// the checkpoint is cancelled at shutdown, even if not all of its blocks were sent yet
+					checkpointBlockID, err := deps.Coordinator.IssueCheckpointWithContext(ctx, lastCheckpointIndex, lastCheckpointBlockID, tips)
 					if err != nil {
 						// issuing checkpoint failed => not critical
 						CoreComponent.LogWarn(err)
@@ -350,6 +495,15 @@ func run() error {
 					}
 					lastCheckpointIndex++
 					lastCheckpointBlockID = checkpointBlockID
+
+					if deps.Coordinator.ShouldForceMilestone() {
+						// too many checkpoints since the last milestone, issue the next milestone immediately
+						select {
+						case nextMilestoneSignal <- struct{}{}:
+						default:
+							// do not block if already another signal is waiting
+						}
+					}
 				}()
 
 			case <-nextMilestoneSignal:
@@ -423,6 +577,20 @@ func run() error {
 		}
 
 		detachEvents()
+
+		shutdownCtx, shutdownCtxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCtxCancel()
+
+		if err := deps.Coordinator.Shutdown(shutdownCtx); err != nil {
+			CoreComponent.LogWarn(err)
+		}
+
+		// the remote and the HSM signing providers hold a connection or a session
+		if closer, ok := milestoneSignerProvider.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				CoreComponent.LogWarnf("failed to close signing provider: %s", err)
+			}
+		}
 	}, daemon.PriorityStopCoordinator); err != nil {
 		CoreComponent.LogPanicf("failed to start worker: %s", err)
 	}
@@ -430,6 +598,46 @@ func run() error {
 	return nil
 }
 
+// loadHSMSignerConfig loads the configuration of the HSM signing provider.
+// The PIN of the token is loaded from the environment.
+func loadHSMSignerConfig() (*coordinator.HSMSignerConfig, error) {
+	if ParamsCoordinator.Signing.HSM.ModulePath == "" {
+		return nil, errors.New("no PKCS#11 module given for HSM signing provider")
+	}
+
+	pin, exists := os.LookupEnv("COO_HSM_PIN")
+	if !exists {
+		return nil, errors.New("environment variable 'COO_HSM_PIN' not set")
+	}
+
+	if len(ParamsCoordinator.Signing.HSM.KeyLabels) == 0 {
+		return nil, errors.New("no key labels given for HSM signing provider")
+	}
+
+	keyLabels := make(map[iotago.MilestonePublicKey]string, len(ParamsCoordinator.Signing.HSM.KeyLabels))
+	for pubKeyHex, label := range ParamsCoordinator.Signing.HSM.KeyLabels {
+		pubKeyBytes, err := iotago.DecodeHex(pubKeyHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key for HSM key %s: %w", label, err)
+		}
+
+		var pubKey iotago.MilestonePublicKey
+		if len(pubKeyBytes) != len(pubKey) {
+			return nil, fmt.Errorf("wrong public key length for HSM key %s", label)
+		}
+		copy(pubKey[:], pubKeyBytes)
+
+		keyLabels[pubKey] = label
+	}
+
+	return &coordinator.HSMSignerConfig{
+		ModulePath: ParamsCoordinator.Signing.HSM.ModulePath,
+		SlotID:     ParamsCoordinator.Signing.HSM.SlotID,
+		PIN:        pin,
+		KeyLabels:  keyLabels,
+	}, nil
+}
+
 // loadEd25519PrivateKeysFromEnvironment loads ed25519 private keys from the given environment variable.
 func loadEd25519PrivateKeysFromEnvironment(name string) ([]ed25519.PrivateKey, error) {
 
@@ -482,7 +690,16 @@ func initSigningProvider(signingProviderType string, remoteEndpoint string, keyM
 			return nil, errors.New("no address given for remote signing provider")
 		}
 
-		return coordinator.NewInsecureRemoteEd25519MilestoneSignerProvider(remoteEndpoint, keyManager, milestonePublicKeyCount), nil
+		// insecure because this RPC remote should be local; in turns, it employs TLS mutual authentication to reach the actual signers.
+		return coordinator.NewRemoteSigner(remoteEndpoint, keyManager, milestonePublicKeyCount, grpc.WithTransportCredentials(insecure.NewCredentials())), nil
+
+	case "hsm":
+		hsmConfig, err := loadHSMSignerConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		return coordinator.NewHSMSigner(hsmConfig, keyManager, milestonePublicKeyCount)
 
 	default:
 		return nil, fmt.Errorf("unknown milestone signing provider: %s", signingProviderType)
@@ -507,6 +724,11 @@ func sendBlock(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.Bl
 	var blockID iotago.BlockID
 	blockID, err = deps.NodeBridge.SubmitBlock(CoreComponent.Daemon().ContextStopped(), block)
 	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			// the node rejected the block, so it certainly didn't reach the network
+			return iotago.EmptyBlockID(), fmt.Errorf("%w: %s", coordinator.ErrBlockNotSent, err)
+		}
+
 		return iotago.EmptyBlockID(), err
 	}
 
@@ -574,8 +796,15 @@ func configureEvents() {
 		CoreComponent.LogInfof("checkpoint (%d) block issued (%d/%d): %v", checkpointIndex+1, tipIndex+1, tipsTotal, blockID.ToHex())
 	})
 
-	onIssuedMilestone = events.NewClosure(func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, blockID iotago.BlockID) {
-		CoreComponent.LogInfof("milestone issued (%d) MilestoneID: %s, BlockID: %v", index, iotago.EncodeHex(milestoneID[:]), blockID.ToHex())
+	onStatePersistFailed = events.NewClosure(func(state coordinator.State, err error) {
+		// log the state, so the operator can restore the state file manually
+		stateJSON, errJSON := json.Marshal(&state)
+		if errJSON != nil {
+			CoreComponent.LogErrorf("unable to persist coordinator state of milestone %d: %s", state.LatestMilestoneIndex, err)
+
+			return
+		}
+		CoreComponent.LogErrorf("unable to persist coordinator state of milestone %d: %s, state: %s", state.LatestMilestoneIndex, err, stateJSON)
 	})
 }
 
@@ -583,12 +812,12 @@ func attachEvents() {
 	deps.TangleListener.Events.BlockSolid.Hook(onBlockSolid)
 	deps.NodeBridge.Events.ConfirmedMilestoneChanged.Hook(onConfirmedMilestoneChanged)
 	deps.Coordinator.Events.IssuedCheckpointBlock.Hook(onIssuedCheckpoint)
-	deps.Coordinator.Events.IssuedMilestone.Hook(onIssuedMilestone)
+	deps.Coordinator.Events.StatePersistFailed.Hook(onStatePersistFailed)
 }
 
 func detachEvents() {
 	deps.TangleListener.Events.BlockSolid.Detach(onBlockSolid)
 	deps.NodeBridge.Events.ConfirmedMilestoneChanged.Detach(onConfirmedMilestoneChanged)
 	deps.Coordinator.Events.IssuedCheckpointBlock.Detach(onIssuedCheckpoint)
-	deps.Coordinator.Events.IssuedMilestone.Detach(onIssuedMilestone)
+	deps.Coordinator.Events.StatePersistFailed.Detach(onStatePersistFailed)
 }
@@ -8,23 +8,66 @@ import (
 )
 
 type Quorum struct {
-	Enabled bool                                         `default:"false" usage:"whether the coordinator quorum is enabled"`
-	Groups  map[string][]*coordinator.QuorumClientConfig `noflag:"true" usage:"defines the quorum groups used to ask other nodes for correct ledger state of the coordinator."`
-	Timeout time.Duration                                `default:"2s" usage:"the timeout until a node in the quorum must have answered"`
+	Enabled          bool                                         `default:"false" usage:"whether the coordinator quorum is enabled"`
+	Groups           map[string][]*coordinator.QuorumClientConfig `noflag:"true" usage:"defines the quorum groups used to ask other nodes for correct ledger state of the coordinator."`
+	Timeout          time.Duration                                `default:"2s" usage:"the timeout until a node in the quorum must have answered"`
+	WeightThreshold  int                                          `default:"0" usage:"the weight of agreeing nodes needed to accept the merkle roots of a quorum group (0 = all answering nodes need to agree)"`
+	ConcurrencyLimit int                                          `default:"0" usage:"the maximum amount of requests to nodes in the quorum in flight at the same time (0 = unlimited)"`
+	FailClosed       bool                                         `default:"false" usage:"whether the coordinator stops if no quorum group answers in time, instead of retrying the milestone"`
+	Compression      bool                                         `default:"false" usage:"whether the requests to the nodes in the quorum and their responses are compressed with gzip"`
+	HealthPoll       time.Duration                                `default:"0s" usage:"the interval the health of the nodes in the quorum is polled between milestones (0 = disabled)"`
+	ClockSkew        time.Duration                                `default:"0s" usage:"the difference between the milestone timestamp and the clock of a node in the quorum that is logged as a warning (0 = disabled)"`
+	CircuitBreaker   struct {
+		FailureThreshold int           `default:"0" usage:"the amount of consecutive failures after which a node in the quorum is skipped (0 = disabled)"`
+		Cooldown         time.Duration `default:"1m" usage:"the duration a node in the quorum is skipped before it is asked again"`
+	}
+	ResultCache struct {
+		Size int           `default:"0" usage:"the maximum amount of cached successful quorum checks (0 = disabled)"`
+		TTL  time.Duration `default:"10s" usage:"the duration a successful quorum check is cached"`
+	}
+	Transport struct {
+		MaxIdleConnsPerHost int           `default:"2" usage:"the maximum amount of idle connections kept alive per node in the quorum"`
+		IdleConnTimeout     time.Duration `default:"90s" usage:"the duration an idle connection to a node in the quorum is kept alive"`
+	}
 }
 
 type ParametersCoordinator struct {
-	StateFilePath string        `default:"coordinator.state" usage:"the path to the state file of the coordinator"`
-	Interval      time.Duration `default:"5s" usage:"the interval milestones are issued"`
-	Signing       struct {
-		Provider      string        `default:"local" usage:"the signing provider the coordinator uses to sign a milestone (local/remote)"`
+	StateFilePath string `default:"coordinator.state" usage:"the path to the state file of the coordinator"`
+	StateBackups  int    `default:"0" usage:"the amount of previous state files that are kept as backups (0 = disabled)"`
+	StateWrite    struct {
+		RetryAmount  int           `default:"3" usage:"the amount of attempts to write the state file after a milestone was sent, before the coordinator shuts down"`
+		RetryBackoff time.Duration `default:"500ms" usage:"the time to wait between attempts to write the state file"`
+	}
+	Interval       time.Duration `default:"5s" usage:"the interval milestones are issued"`
+	IntervalJitter float64       `default:"0" usage:"the fraction of the interval that is randomly added or subtracted to desynchronize several coordinators, must be in [0, 1) (0 = disabled)"`
+	MinInterval    time.Duration `default:"0s" usage:"the minimum time between two milestones, milestones issued sooner are rejected (0 = disabled)"`
+	CrashRecovery  bool          `default:"false" usage:"whether a milestone that was issued before a crash, but is missing in the state file, is adopted at startup"`
+	MerkleTimeout  time.Duration `default:"0s" usage:"the maximum duration of the white flag computation of a milestone, the coordinator stops if it is exceeded (0 = disabled)"`
+	Signing        struct {
+		Provider      string        `default:"local" usage:"the signing provider the coordinator uses to sign a milestone (local/remote/hsm)"`
 		RemoteAddress string        `default:"localhost:12345" usage:"the address of the remote signing provider (insecure connection!)"`
 		RetryTimeout  time.Duration `default:"2s" usage:"defines the timeout between signing retries"`
 		RetryAmount   int           `default:"10" usage:"defines the number of signing retries to perform before shutting down the node"`
+		MaxBackoff    time.Duration `default:"0s" usage:"the maximum time between signing retries with exponential backoff, starting at the retry timeout (0 = fixed retry timeout)"`
+		Parallelism   int           `default:"0" usage:"the maximum amount of keys signing a milestone concurrently, every key is signed in a separate request (0 or 1 = all keys in a single request)"`
+		HSM           struct {
+			ModulePath string            `default:"" usage:"the path to the PKCS#11 module of the HSM signing provider"`
+			SlotID     uint              `default:"0" usage:"the slot of the HSM token holding the milestone keys"`
+			KeyLabels  map[string]string `noflag:"true" usage:"the labels of the private keys in the HSM, mapped by their hex encoded public keys"`
+		} `name:"hsm"`
+	}
+	Quorum  Quorum
+	Webhook struct {
+		URL          string            `default:"" usage:"the URL issued milestones are posted to (empty = disabled)"`
+		Headers      map[string]string `noflag:"true" usage:"additional headers of the webhook requests"`
+		RetryAmount  int               `default:"3" usage:"the amount of times to retry a failed webhook request"`
+		RetryBackoff time.Duration     `default:"1s" usage:"the time to wait between retries of a failed webhook request"`
 	}
-	Quorum      Quorum
 	Checkpoints struct {
-		MaxTrackedBlocks int `default:"10000" usage:"maximum amount of known blocks for milestone tipselection. If this limit is exceeded, a new checkpoint is issued."`
+		MaxTrackedBlocks    int    `default:"10000" usage:"maximum amount of known blocks for milestone tipselection. If this limit is exceeded, a new checkpoint is issued."`
+		ForceMilestoneAfter int    `default:"0" usage:"the amount of checkpoints after which a milestone is issued immediately (0 = disabled)"`
+		MaxPerInterval      int    `default:"0" usage:"the maximum amount of checkpoints issued between two milestones (0 = unlimited)"`
+		Strategy            string `default:"chained" usage:"how the blocks of a checkpoint reference each other (chained/fanout)"`
 	}
 	TipSel struct {
 		MinHeaviestBranchUnreferencedBlocksThreshold int           `default:"20" usage:"minimum threshold of unreferenced blocks in the heaviest branch"`
@@ -46,3 +89,8 @@ var params = &app.ComponentParams{
 	},
 	Masked: nil,
 }
+
+func init() {
+	ParamsCoordinator.Signing.HSM.KeyLabels = make(map[string]string)
+	ParamsCoordinator.Webhook.Headers = make(map[string]string)
+}
@@ -0,0 +1,145 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+)
+
+// BackPressureFunc is a function which tells the Coordinator
+// to stop issuing milestones and checkpoints under high load.
+type BackPressureFunc func() bool
+
+// namedBackPressureFunc is a BackPressureFunc with a name to report which one signaled congestion.
+type namedBackPressureFunc struct {
+	name string
+	fn   BackPressureFunc
+}
+
+// backPressureCacheEntry is the cached result of the back pressure functions.
+type backPressureCacheEntry struct {
+	// the name of the function that signaled congestion, empty if there was no congestion.
+	name string
+	// the time the result expires.
+	expiresAt time.Time
+}
+
+// AddBackPressureFunc adds a BackPressureFunc.
+// This function can be called multiple times to add additional BackPressureFunc.
+// The function gets a generated name, use AddNamedBackPressureFunc to give it a meaningful name.
+func (coo *Coordinator) AddBackPressureFunc(bpFunc BackPressureFunc) {
+	coo.backpressureLock.Lock()
+	defer coo.backpressureLock.Unlock()
+
+	coo.backpressureCache = nil
+	coo.unnamedBackPressureFuncsCount++
+	coo.backpressureFuncs = append(coo.backpressureFuncs, &namedBackPressureFunc{
+		name: fmt.Sprintf("unnamed backpressure func %d", coo.unnamedBackPressureFuncsCount),
+		fn:   bpFunc,
+	})
+}
+
+// AddNamedBackPressureFunc adds a BackPressureFunc with the given name.
+// The name is reported if the function signals congestion.
+// An existing BackPressureFunc with the same name is replaced.
+func (coo *Coordinator) AddNamedBackPressureFunc(name string, bpFunc BackPressureFunc) {
+	coo.backpressureLock.Lock()
+	defer coo.backpressureLock.Unlock()
+
+	coo.backpressureCache = nil
+
+	for i, f := range coo.backpressureFuncs {
+		if f.name == name {
+			// create a new slice, so a running check is not affected
+			backpressureFuncs := append([]*namedBackPressureFunc{}, coo.backpressureFuncs...)
+			backpressureFuncs[i] = &namedBackPressureFunc{name: name, fn: bpFunc}
+			coo.backpressureFuncs = backpressureFuncs
+
+			return
+		}
+	}
+
+	coo.backpressureFuncs = append(coo.backpressureFuncs, &namedBackPressureFunc{name: name, fn: bpFunc})
+}
+
+// RemoveBackPressureFunc removes the BackPressureFunc with the given name.
+// Returns false if no BackPressureFunc with that name exists.
+func (coo *Coordinator) RemoveBackPressureFunc(name string) bool {
+	coo.backpressureLock.Lock()
+	defer coo.backpressureLock.Unlock()
+
+	coo.backpressureCache = nil
+
+	for i, f := range coo.backpressureFuncs {
+		if f.name == name {
+			// create a new slice, so a running check is not affected
+			backpressureFuncs := make([]*namedBackPressureFunc, 0, len(coo.backpressureFuncs)-1)
+			backpressureFuncs = append(backpressureFuncs, coo.backpressureFuncs[:i]...)
+			coo.backpressureFuncs = append(backpressureFuncs, coo.backpressureFuncs[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkBackPressureFunctions checks whether any back pressure function is signaling congestion.
+// Returns the name of the first function that signaled congestion, or an empty string otherwise.
+// The result is cached as configured via WithBackPressureCacheTTL, unless bypassCache is set.
+func (coo *Coordinator) checkBackPressureFunctions(bypassCache bool) string {
+	now := coo.opts.clock.Now()
+
+	// the functions are called without holding the lock, they could be slow
+	coo.backpressureLock.RLock()
+	backpressureFuncs := coo.backpressureFuncs
+	cache := coo.backpressureCache
+	coo.backpressureLock.RUnlock()
+
+	if !bypassCache && cache != nil && now.Before(cache.expiresAt) {
+		return cache.name
+	}
+
+	name := ""
+	for _, f := range backpressureFuncs {
+		if coo.callBackPressureFunc(f) {
+			name = f.name
+
+			break
+		}
+	}
+
+	if coo.opts.backpressureCacheTTL > 0 {
+		coo.backpressureLock.Lock()
+		coo.backpressureCache = &backPressureCacheEntry{name: name, expiresAt: now.Add(coo.opts.backpressureCacheTTL)}
+		coo.backpressureLock.Unlock()
+	}
+
+	return name
+}
+
+// callBackPressureFunc calls the back pressure function, bounded by the timeout configured via WithBackPressureTimeout.
+// The check is fail-safe, a function that exceeds the timeout is treated as signaling congestion.
+// The function keeps running in the background in that case, its result is discarded.
+func (coo *Coordinator) callBackPressureFunc(f *namedBackPressureFunc) bool {
+	if coo.opts.backpressureTimeout <= 0 {
+		return f.fn()
+	}
+
+	// buffered, so the goroutine doesn't leak if the function returns after the timeout
+	resultChan := make(chan bool, 1)
+	go func() {
+		resultChan <- f.fn()
+	}()
+
+	timer := time.NewTimer(coo.opts.backpressureTimeout)
+	defer timer.Stop()
+
+	select {
+	case congested := <-resultChan:
+		return congested
+	case <-timer.C:
+		coo.logWarnw("back pressure function timed out, treating it as congested", logFieldBackPressure, f.name, logFieldDurationMs, coo.opts.backpressureTimeout.Milliseconds())
+
+		return true
+	}
+}
@@ -0,0 +1,83 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrMilestoneChainBroken is returned if the milestones do not form a valid chain.
+	ErrMilestoneChainBroken = errors.New("milestone chain broken")
+)
+
+// FetchMilestoneFunc should return the milestone with the given index.
+type FetchMilestoneFunc = func(index iotago.MilestoneIndex) (*iotago.Milestone, error)
+
+// VerifyChain verifies that the milestones from the given index up to the latest milestone
+// of the coordinator form a valid chain. Every milestone needs to reference the previous one
+// and must be signed by the valid public keys for its index.
+// The first broken link is returned as an error.
+func (coo *Coordinator) VerifyChain(ctx context.Context, fromIndex iotago.MilestoneIndex, fetchMilestone FetchMilestoneFunc) error {
+
+	coo.milestoneLock.Lock()
+	if coo.state == nil {
+		coo.milestoneLock.Unlock()
+
+		return ErrStateNotInitialized
+	}
+	latestMilestoneIndex := coo.state.LatestMilestoneIndex
+	latestMilestoneID := coo.state.LatestMilestoneID
+	coo.milestoneLock.Unlock()
+
+	if fromIndex == 0 {
+		// there is no milestone with index 0
+		fromIndex = 1
+	}
+
+	if fromIndex > latestMilestoneIndex {
+		return fmt.Errorf("%w: start index %d is above the latest milestone index %d", ErrMilestoneChainBroken, fromIndex, latestMilestoneIndex)
+	}
+
+	var previousMilestoneID iotago.MilestoneID
+	for index := fromIndex; index <= latestMilestoneIndex; index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		milestone, err := fetchMilestone(index)
+		if err != nil {
+			return fmt.Errorf("failed to fetch milestone %d: %w", index, err)
+		}
+
+		if milestone == nil {
+			return fmt.Errorf("failed to fetch milestone %d: milestone not found", index)
+		}
+
+		if milestone.Index != index {
+			return fmt.Errorf("%w: milestone index mismatch, expected: %d, got: %d", ErrMilestoneChainBroken, index, milestone.Index)
+		}
+
+		if index != fromIndex && milestone.PreviousMilestoneID != previousMilestoneID {
+			return fmt.Errorf("%w: milestone %d does not reference the previous milestone, expected: %s, got: %s", ErrMilestoneChainBroken, index, previousMilestoneID.ToHex(), milestone.PreviousMilestoneID.ToHex())
+		}
+
+		if err := milestone.VerifySignatures(coo.signerProvider.PublicKeysCount(), coo.signerProvider.MilestoneIndexSigner(index).PublicKeysSet()); err != nil {
+			return fmt.Errorf("%w: invalid signatures in milestone %d: %s", ErrMilestoneChainBroken, index, err)
+		}
+
+		previousMilestoneID, err = milestone.ID()
+		if err != nil {
+			return fmt.Errorf("failed to compute milestone ID of milestone %d: %w", index, err)
+		}
+	}
+
+	if previousMilestoneID != latestMilestoneID {
+		return fmt.Errorf("%w: latest milestone ID mismatch, expected: %s, got: %s", ErrMilestoneChainBroken, latestMilestoneID.ToHex(), previousMilestoneID.ToHex())
+	}
+
+	return nil
+}
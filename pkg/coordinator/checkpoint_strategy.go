@@ -0,0 +1,47 @@
+package coordinator
+
+import (
+	"fmt"
+)
+
+// CheckpointStrategy defines how the blocks of a checkpoint reference each other.
+//
+// Chained checkpoint blocks form a chain, so the last block of a checkpoint references all of its tips,
+// and the next milestone only needs to reference that single block. But every block can only be built
+// after the previous one was sent.
+//
+// Fan out checkpoint blocks all reference the block passed as the last checkpoint, e.g. the last milestone,
+// so a slow or lost block doesn't delay the others. But the next milestone only references the last block
+// of the checkpoint directly, the other blocks need to be picked up by the tip selection.
+type CheckpointStrategy int
+
+const (
+	// CheckpointStrategyChained chains every checkpoint block to the previous one.
+	CheckpointStrategyChained CheckpointStrategy = iota
+	// CheckpointStrategyFanOut references the last checkpoint or milestone from every checkpoint block.
+	CheckpointStrategyFanOut
+)
+
+// String returns the name of the checkpoint strategy.
+func (s CheckpointStrategy) String() string {
+	switch s {
+	case CheckpointStrategyChained:
+		return "chained"
+	case CheckpointStrategyFanOut:
+		return "fanout"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseCheckpointStrategy returns the checkpoint strategy with the given name.
+func ParseCheckpointStrategy(name string) (CheckpointStrategy, error) {
+	switch name {
+	case CheckpointStrategyChained.String():
+		return CheckpointStrategyChained, nil
+	case CheckpointStrategyFanOut.String():
+		return CheckpointStrategyFanOut, nil
+	default:
+		return 0, fmt.Errorf("unknown checkpoint strategy: %s", name)
+	}
+}
@@ -0,0 +1,19 @@
+package coordinator
+
+import (
+	"time"
+)
+
+// Clock provides the current time to the coordinator.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// realClock is the Clock using the system time.
+type realClock struct{}
+
+// Now returns the current system time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
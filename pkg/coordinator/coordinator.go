@@ -3,11 +3,14 @@ package coordinator
 import (
 	"context"
 	"fmt"
-	"math"
+	"net/http"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/iotaledger/hive.go/core/events"
 	"github.com/iotaledger/hive.go/core/ioutils"
@@ -21,20 +24,48 @@ import (
 	_ "golang.org/x/crypto/blake2b"
 )
 
-// BackPressureFunc is a function which tells the Coordinator
-// to stop issuing milestones and checkpoints under high load.
-type BackPressureFunc func() bool
-
 // SendBlockFunc is a function which sends a block to the network.
+// If the block certainly didn't reach the network, e.g. because the node rejected it, the error should wrap ErrBlockNotSent.
+// Other errors are treated as if the block may have been sent.
 type SendBlockFunc = func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error)
 
+// SendBlockWithContextFunc is a function which sends a block to the network and can be cancelled via the context.
+type SendBlockWithContextFunc = func(ctx context.Context, block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error)
+
+// PreSendHookFunc is called with a signed milestone block before it is sent to the network.
+// Returning an error aborts the issuance of the milestone.
+type PreSendHookFunc = func(block *iotago.Block, index iotago.MilestoneIndex) error
+
+// StateTransitionObserverFunc is called with the state of the coordinator before and after every issued milestone.
+type StateTransitionObserverFunc = func(oldState State, newState State)
+
+// ReceiptValidatorFunc is called with the receipt of a milestone and the treasury transaction embedded into it.
+// Returning an error aborts the issuance of the milestone.
+type ReceiptValidatorFunc = func(receipt *iotago.ReceiptMilestoneOpt, transaction *iotago.TreasuryTransaction) error
+
+// PostSendHookFunc is called after a milestone was sent to the network and the coordinator state was stored.
+type PostSendHookFunc = func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, blockID iotago.BlockID) error
+
+// MilestoneMetadataFunc returns the metadata that is embedded into the milestone with the given index.
+type MilestoneMetadataFunc = func(index iotago.MilestoneIndex) []byte
+
 // LatestMilestoneInfo contains the info of the latest milestone the connected node knows.
 type LatestMilestoneInfo struct {
 	Index       iotago.MilestoneIndex
 	Timestamp   uint32
 	MilestoneID iotago.MilestoneID
+	// BlockID is the optional ID of the block containing the milestone.
+	// If set, the first milestone references it at bootstrap.
+	BlockID iotago.BlockID
 }
 
+// BlockSolidFunc should return whether the block with the given ID is solid in the node.
+type BlockSolidFunc = func(blockID iotago.BlockID) (bool, error)
+
+// BlockMilestoneIDFunc returns the ID of the milestone contained in the block with the given ID.
+// Returns false if the block is unknown or doesn't contain a milestone.
+type BlockMilestoneIDFunc = func(blockID iotago.BlockID) (iotago.MilestoneID, bool, error)
+
 // LatestTreasuryOutput represents the latest treasury output created by the last milestone that contained a migration.
 type LatestTreasuryOutput struct {
 	MilestoneID iotago.MilestoneID
@@ -47,27 +78,86 @@ type UnspentTreasuryOutputFunc = func() (*LatestTreasuryOutput, error)
 var (
 	// ErrNoTipsGiven is returned when no tips were given to issue a checkpoint.
 	ErrNoTipsGiven = errors.New("no tips given")
+	// ErrCheckpointLimitReached is returned if the maximum amount of checkpoints since the last milestone was issued.
+	ErrCheckpointLimitReached = errors.New("maximum amount of checkpoints per milestone interval reached")
 	// ErrNetworkBootstrapped is returned when the flag for bootstrap network was given, but a state file already exists.
 	ErrNetworkBootstrapped = errors.New("network already bootstrapped")
 	// ErrNodeLoadTooHigh is returned if the backpressure func says the node load is too high.
 	ErrNodeLoadTooHigh = errors.New("node load too high")
+	// ErrTooManyParents is returned if more parents were given than allowed by the protocol.
+	ErrTooManyParents = errors.New("too many parents")
+	// ErrStateAlreadyInitialized is returned if the state of the coordinator was already initialized.
+	ErrStateAlreadyInitialized = errors.New("coordinator state already initialized")
+	// ErrStateNotInitialized is returned if the coordinator state is needed before InitState was called.
+	ErrStateNotInitialized = errors.New("coordinator state not initialized")
+	// ErrCoordinatorPaused is returned if milestones or checkpoints should be issued while the coordinator is paused.
+	ErrCoordinatorPaused = errors.New("coordinator paused")
+	// ErrCoordinatorShutdown is returned if milestones or checkpoints should be issued after the coordinator was shut down.
+	ErrCoordinatorShutdown = errors.New("coordinator shut down")
+	// ErrCoordinatorReadOnly is returned if milestones or checkpoints should be issued while the coordinator is read-only.
+	ErrCoordinatorReadOnly = errors.New("coordinator is read-only")
+	// ErrMilestoneIndexGap is returned if the latest milestone index of the node doesn't match the coordinator state.
+	ErrMilestoneIndexGap = errors.New("milestone index gap detected")
+	// ErrMilestoneMetadataTooLong is returned if the metadata of a milestone exceeds the maximum length allowed by the protocol.
+	ErrMilestoneMetadataTooLong = errors.New("milestone metadata too long")
+	// ErrBootstrapParentMismatch is returned if the parent of the first milestone doesn't contain the expected previous milestone at bootstrap.
+	ErrBootstrapParentMismatch = errors.New("bootstrap parent doesn't reference the previous milestone")
+	// ErrMilestoneParentsMismatch is returned if the parents of a milestone differ from the parents used for the white flag computation.
+	ErrMilestoneParentsMismatch = errors.New("milestone parents don't match the white flag parents")
+	// ErrTreasuryOutputStale is returned if the unspent treasury output doesn't reflect the receipt of the previous migration.
+	ErrTreasuryOutputStale = errors.New("unspent treasury output is stale")
+	// ErrInvalidProtocolParametersUpdate is returned if a pending protocol parameters update is invalid.
+	ErrInvalidProtocolParametersUpdate = errors.New("invalid protocol parameters update")
+	// ErrMilestoneTooEarly is returned if a milestone is issued sooner than the minimum milestone interval after the previous one.
+	ErrMilestoneTooEarly = errors.New("milestone issued too early")
+	// ErrParentsNotSolid is returned if a parent of a strictly issued milestone is not solid in the node.
+	ErrParentsNotSolid = errors.New("milestone parents not solid")
+	// ErrPreviousMilestoneIDMismatch is returned if the previous milestone expected by the caller differs from the coordinator state.
+	ErrPreviousMilestoneIDMismatch = errors.New("previous milestone ID mismatch")
+	// ErrBlockSolidFuncMissing is returned if solid parents are required, but no BlockSolidFunc was configured.
+	ErrBlockSolidFuncMissing = errors.New("no block solid function configured")
+	// ErrMerkleComputeTimeout is returned if the white flag computation of the merkle roots exceeds the configured timeout.
+	ErrMerkleComputeTimeout = errors.New("merkle root computation timed out")
+	// ErrBlockNotSent is wrapped by the send functions if a block certainly didn't reach the network.
+	ErrBlockNotSent = errors.New("block not sent")
+	// ErrMilestoneDeliveryUnknown is returned if a milestone may have been sent although sending it failed,
+	// and it can't be checked whether the milestone was issued before the next milestone is issued.
+	ErrMilestoneDeliveryUnknown = errors.New("milestone delivery unknown")
 )
 
 // Events are the events issued by the coordinator.
+// The handlers are called synchronously. Except for IssuingMilestone, the events raised during an issuance
+// are triggered after the issuance completed, so their handlers can call any method of the coordinator.
 type Events struct {
 	// Fired when a checkpoint block is issued.
 	IssuedCheckpointBlock *events.Event
+	// Fired when a checkpoint block is issued, carries the parents of the block in addition to IssuedCheckpointBlock.
+	IssuedCheckpointBlockDetails *events.Event
 	// Fired when a milestone is issued.
 	IssuedMilestone *events.Event
+	// Fired right before a milestone is sent to the network, even if sending it fails afterwards.
+	// It is fired during the issuance, so its handlers may use the getters of the latest milestone,
+	// but must not call methods that wait for the issuance, e.g. Pause or IssueMilestone.
+	IssuingMilestone *events.Event
+	// Fired when sending a milestone finally failed after all retries.
+	MilestoneSendFailed *events.Event
+	// Fired when the state of a sent milestone could not be written to the state file after all retries.
+	// It carries the new state, so it can be persisted elsewhere before the coordinator shuts down.
+	StatePersistFailed *events.Event
 	// SoftError is triggered when a soft error is encountered.
 	SoftError *events.Event
 	// QuorumFinished is triggered after a coordinator quorum call was finished.
 	QuorumFinished *events.Event
+	// LifecycleStateChanged is triggered on every transition of the coordinator lifecycle.
+	LifecycleStateChanged *events.Event
 }
 
 // IsNodeSyncedFunc should only return true if the node connected to the coordinator is synced.
 type IsNodeSyncedFunc = func() bool
 
+// LatestMilestoneIndexFunc should return the index of the latest milestone the node connected to the coordinator knows.
+type LatestMilestoneIndexFunc = func() iotago.MilestoneIndex
+
 // ProtocolParameteresFunc should return the current valid protocol parameters.
 type ProtocolParameteresFunc = func() *iotago.ProtocolParameters
 
@@ -95,21 +185,91 @@ type Coordinator struct {
 	protoParamsFunc ProtocolParameteresFunc
 	// used to get receipts for the WOTS migration.
 	migratorService *migrator.Service
+	// whether receipts of the migrator service are included in milestones.
+	migratorEnabled bool
 	// used to get the treasury output.
 	treasuryOutputFunc UnspentTreasuryOutputFunc
+	// the treasury output created by the latest issued receipt, which the next unspent treasury output must match.
+	// nil if no receipt was issued since the start or the unspent treasury output was confirmed already.
+	expectedTreasuryOutput *LatestTreasuryOutput
 	// used to sign the milestones.
 	signerProvider MilestoneSignerProvider
 	// the function used to send a block.
 	sendBlockFunc SendBlockFunc
 	// holds the coordinator options.
 	opts *Options
+	// the optional quorum used to check for correct ledger state calculation.
+	quorum *quorum
+	// used to replace the quorum at runtime.
+	quorumLock syncutils.RWMutex
+	// the result of the last quorum check, nil if no quorum check finished yet.
+	lastQuorumResult *QuorumFinishedResult
+	// used to protect the result of the last quorum check.
+	lastQuorumResultLock syncutils.RWMutex
+
+	// the durations of the phases of the last milestone issuance.
+	lastIssuanceTiming *IssuanceTiming
+	// used to protect the durations of the phases of the last milestone issuance.
+	lastIssuanceTimingLock syncutils.RWMutex
 
 	// back pressure functions that signal congestion.
-	backpressureFuncs []BackPressureFunc
+	backpressureFuncs []*namedBackPressureFunc
+	// the amount of back pressure functions that were added without a name.
+	unnamedBackPressureFuncsCount int
+	// used to protect the back pressure functions.
+	backpressureLock syncutils.RWMutex
+	// the cached result of the back pressure functions.
+	backpressureCache *backPressureCacheEntry
 	// state of the coordinator holds information about the last issued milestones.
 	state *State
 	// whether the coordinator was bootstrapped.
 	bootstrapped bool
+	// the index of a milestone that may have been sent although sending it failed (0 = none).
+	// it must be checked whether the milestone was issued, before the index is used again.
+	unconfirmedMilestoneIndex iotago.MilestoneIndex
+	// the amount of checkpoints issued since the last milestone.
+	checkpointsSinceMilestone atomic.Int32
+	// the result of the last check whether the node is synced.
+	lastNodeSyncCheck nodeSyncCheck
+	// used to protect the result of the last check whether the node is synced.
+	lastNodeSyncCheckLock syncutils.RWMutex
+	// the amount of milestones issued since the coordinator was created.
+	issuedSinceStart atomic.Uint64
+	// a copy of the state, which is published whenever the state changes, so the getters don't need the milestone lock.
+	publishedState atomic.Pointer[State]
+	// the events raised while the milestone lock was held, which are triggered after it was released.
+	queuedEvents []func()
+	// used to protect the queued events.
+	queuedEventsLock syncutils.Mutex
+	// the last error of the milestone issuance.
+	lastIssuanceErr error
+	// used to protect the last error of the milestone issuance.
+	lastIssuanceErrLock syncutils.RWMutex
+	// whether the issuance of milestones and checkpoints is paused.
+	paused atomic.Bool
+	// whether the coordinator was shut down.
+	shutdown atomic.Bool
+	// cancelled at shutdown to abort pending signing retries.
+	shutdownCtx context.Context
+	// cancels the shutdown context.
+	shutdownCancel context.CancelFunc
+	// the current state of the coordinator lifecycle.
+	lifecycleState LifecycleState
+	// used to protect the lifecycle state.
+	lifecycleLock syncutils.RWMutex
+	// the interval milestones are issued, can be changed at runtime.
+	milestoneInterval time.Duration
+	// the recent results of the back pressure checks of milestones, used for the adaptive interval.
+	backPressureHistory *backPressureHistory
+	// used to protect the milestone interval and the back pressure history.
+	milestoneIntervalLock syncutils.RWMutex
+	// the tracer used to trace the milestone issuance.
+	tracer trace.Tracer
+	// the latest issued milestones, nil if the history is disabled.
+	history *milestoneHistory
+	// the protocol parameters milestone option that is embedded in the milestone at its target index.
+	// it is cleared after the milestone was issued, nil if no update is pending.
+	pendingProtocolParametersOpt *iotago.ProtocolParamsMilestoneOpt
 	// events of the coordinator.
 	Events *Events
 }
@@ -129,6 +289,10 @@ var defaultOptions = []Option{
 	WithMilestoneInterval(defaultMilestoneInterval),
 	WithSigningRetryAmount(10),
 	WithSigningRetryTimeout(2 * time.Second),
+	WithClock(realClock{}),
+	WithSendBlockRetry(1, 0),
+	WithStateWriteRetry(1, 0),
+	WithMetrics(noopMetrics{}),
 }
 
 // Options define options for the Coordinator.
@@ -137,14 +301,118 @@ type Options struct {
 	logger *logger.Logger
 	// the path to the state file of the coordinator.
 	stateFilePath string
+	// the amount of previous state files that are kept as backups (0 = disabled).
+	stateBackups int
 	// the interval milestones are issued.
 	milestoneInterval time.Duration
+	// the fraction of the interval that is randomly added or subtracted (0 = disabled).
+	intervalJitter float64
 	// the timeout between signing retries.
 	signingRetryTimeout time.Duration
 	// the amount of times to retry signing before bailing and shutting down the Coordinator.
 	signingRetryAmount int
-	// the optional quorum used by the coordinator to check for correct ledger state calculation.
-	quorum *quorum
+	// the maximum time between signing retries if the backoff grows exponentially (0 = fixed signing retry timeout).
+	signingMaxBackoff time.Duration
+	// the maximum amount of public keys signing a milestone concurrently (0 or 1 = all keys in a single call).
+	signingParallelism int
+	// whether the quorum is used by the coordinator to check for correct ledger state calculation.
+	quorumEnabled bool
+	// the groups of the quorum.
+	quorumGroups map[string][]*QuorumClientConfig
+	// the maximum timeout of a quorum request.
+	quorumTimeout time.Duration
+	// the optional transport shared by the quorum clients.
+	quorumTransport *http.Transport
+	// the amount of checkpoints after which a milestone should be forced (0 = disabled).
+	forceMilestoneAfterCheckpoints int
+	// the maximum amount of checkpoints issued between two milestones (0 = unlimited).
+	maxCheckpointsPerInterval int
+	// how the blocks of a checkpoint reference each other.
+	checkpointStrategy CheckpointStrategy
+	// the optional callback invoked on every transition of the coordinator lifecycle.
+	lifecycleStateChangedFunc LifecycleStateChangedFunc
+	// the maximum amount of parents of a block (0 = protocol default).
+	maxParentsCount int
+	// the optional function used to send a block with a context, replaces the SendBlockFunc if set.
+	sendBlockWithContextFunc SendBlockWithContextFunc
+	// the amount of consecutive failures after which a quorum client is skipped (0 = disabled).
+	quorumCircuitBreakerThreshold int
+	// the duration a quorum client is skipped after its circuit breaker opened.
+	quorumCircuitBreakerCooldown time.Duration
+	// the weight of agreeing quorum clients needed to accept the merkle roots of a group (0 = all answering clients need to agree).
+	quorumWeightThreshold int
+	// the maximum amount of quorum requests in flight across all groups (0 = unlimited).
+	quorumConcurrencyLimit int
+	// the maximum amount of cached successful quorum checks (0 = disabled).
+	quorumResultCacheSize int
+	// the duration a successful quorum check is cached.
+	quorumResultCacheTTL time.Duration
+	// whether a quorum without any answering group is a critical error.
+	quorumFailClosed bool
+	// the clock skew to a client in the quorum that is reported as a warning (0 = disabled).
+	quorumClockSkewThreshold time.Duration
+	// whether the requests to the clients in the quorum and their responses are compressed with gzip.
+	quorumCompression bool
+	// the interval the health of the clients in the quorum is polled between milestones (0 = disabled).
+	quorumHealthPollInterval time.Duration
+	// the clock used to determine the timestamps of milestones.
+	clock Clock
+	// the amount of attempts to send a milestone block before bailing and shutting down the Coordinator.
+	sendBlockRetryAttempts int
+	// the time to wait between attempts to send a milestone block.
+	sendBlockRetryBackoff time.Duration
+	// the amount of attempts to write the state file after a milestone was sent, before shutting down the Coordinator.
+	stateWriteRetryAttempts int
+	// the time to wait between attempts to write the state file.
+	stateWriteRetryBackoff time.Duration
+	// the optional function used to adopt an already issued milestone after a crash.
+	milestoneExistsFunc MilestoneExistsFunc
+	// the duration the result of the back pressure functions is cached (0 = disabled).
+	backpressureCacheTTL time.Duration
+	// whether the cached result of the back pressure functions is ignored for milestones.
+	backpressureCacheBypassForMilestones bool
+	// the maximum duration of a single back pressure function (0 = disabled).
+	backpressureTimeout time.Duration
+	// the optional signer provider that replaces the one passed to New.
+	signerProvider MilestoneSignerProvider
+	// the optional hook called before a milestone is sent to the network.
+	preSendHook PreSendHookFunc
+	// the optional hook called after a milestone was sent to the network.
+	postSendHook PostSendHookFunc
+	// the optional observer of the state transitions of the coordinator.
+	stateTransitionObserver StateTransitionObserverFunc
+	// the optional function used to validate the receipts of milestones.
+	receiptValidator ReceiptValidatorFunc
+	// the minimum interval milestones are issued if the interval is adaptive.
+	adaptiveIntervalMin time.Duration
+	// the maximum interval milestones are issued if the interval is adaptive (0 = disabled).
+	adaptiveIntervalMax time.Duration
+	// the optional function that returns the metadata embedded into milestones.
+	milestoneMetadataFunc MilestoneMetadataFunc
+	// the optional tracer provider used to trace the milestone issuance.
+	tracerProvider trace.TracerProvider
+	// used to record metrics of the milestone issuance.
+	metrics Metrics
+	// the optional function used to check the coordinator state against the latest milestone index of the node.
+	latestMilestoneIndexFunc LatestMilestoneIndexFunc
+	// the optional milestone ID referenced by the first milestone at bootstrap instead of the latest milestone of the node.
+	bootstrapPreviousMilestoneID *iotago.MilestoneID
+	// the minimum time between the timestamps of two milestones (0 = disabled).
+	minMilestoneInterval time.Duration
+	// the amount of issued milestones kept in the history (0 = disabled).
+	historySize int
+	// the age after which only the index and the timestamp of milestones in the history are kept (0 = disabled).
+	historyCompactAge time.Duration
+	// the maximum duration of the white flag computation of the merkle roots (0 = disabled).
+	merkleComputeTimeout time.Duration
+	// the optional function used to check that the parents of a strictly issued milestone are solid.
+	blockSolidFunc BlockSolidFunc
+	// the optional function used to check the parent of the first milestone at bootstrap.
+	bootstrapParentResolver BlockMilestoneIDFunc
+	// the milestone index at which the pending protocol parameters update is embedded.
+	protocolParametersUpdateTargetIndex iotago.MilestoneIndex
+	// the optional protocol parameters embedded in the milestone at the target index.
+	protocolParametersUpdate *iotago.ProtocolParameters
 }
 
 // applies the given Option.
@@ -168,6 +436,14 @@ func WithStateFilePath(stateFilePath string) Option {
 	}
 }
 
+// WithStateBackups defines the amount of previous state files that are kept as backups (0 = disabled).
+// The backups are rotated after every milestone, the most recent one is "<stateFilePath>.1".
+func WithStateBackups(stateBackups int) Option {
+	return func(opts *Options) {
+		opts.stateBackups = stateBackups
+	}
+}
+
 // WithMilestoneInterval defines interval milestones are issued.
 func WithMilestoneInterval(milestoneInterval time.Duration) Option {
 	return func(opts *Options) {
@@ -175,6 +451,16 @@ func WithMilestoneInterval(milestoneInterval time.Duration) Option {
 	}
 }
 
+// WithIntervalJitter defines the fraction of the milestone interval that is randomly added to or subtracted from it,
+// e.g. 0.1 returns an interval between 90% and 110% of the milestone interval.
+// This desynchronizes the issuance of several coordinators, e.g. in test networks.
+// The fraction must be in [0, 1), a value of 0 disables the jitter.
+func WithIntervalJitter(fraction float64) Option {
+	return func(opts *Options) {
+		opts.intervalJitter = fraction
+	}
+}
+
 // WithSigningRetryTimeout defines signing retry timeout.
 func WithSigningRetryTimeout(timeout time.Duration) Option {
 	return func(opts *Options) {
@@ -189,22 +475,395 @@ func WithSigningRetryAmount(amount int) Option {
 	}
 }
 
+// WithSigningMaxBackoff enables an exponential backoff with jitter between signing retries,
+// starting at the signing retry timeout and capped by the given maximum.
+// A maximum of 0 keeps the fixed signing retry timeout.
+func WithSigningMaxBackoff(maxBackoff time.Duration) Option {
+	return func(opts *Options) {
+		opts.signingMaxBackoff = maxBackoff
+	}
+}
+
+// WithSigningParallelism signs milestones with every public key in a separate call of the signer,
+// using up to the given amount of concurrent calls. This shortens the signing phase if the signer is slow per key,
+// e.g. a remote signer. The signatures are sorted by public key, so the milestone doesn't depend on the order.
+// A value of 0 or 1 signs with all public keys in a single call.
+func WithSigningParallelism(parallelism int) Option {
+	return func(opts *Options) {
+		opts.signingParallelism = parallelism
+	}
+}
+
+// WithForceMilestoneAfterCheckpoints defines the amount of checkpoints after which
+// ShouldForceMilestone signals that a milestone must be issued.
+// A value of 0 disables the signal.
+func WithForceMilestoneAfterCheckpoints(checkpoints int) Option {
+	return func(opts *Options) {
+		opts.forceMilestoneAfterCheckpoints = checkpoints
+	}
+}
+
+// WithMaxCheckpointsPerInterval defines the maximum amount of checkpoints issued between two milestones.
+// Further checkpoints are rejected with a soft error until the next milestone is issued,
+// which bounds the amount of blocks issued by the coordinator, e.g. if the network is flooded with tips.
+// A value of 0 disables the limit.
+func WithMaxCheckpointsPerInterval(checkpoints int) Option {
+	return func(opts *Options) {
+		opts.maxCheckpointsPerInterval = checkpoints
+	}
+}
+
+// WithCheckpointStrategy defines how the blocks of a checkpoint reference each other.
+// The default is CheckpointStrategyChained, see CheckpointStrategy for the trade-offs.
+func WithCheckpointStrategy(strategy CheckpointStrategy) Option {
+	return func(opts *Options) {
+		opts.checkpointStrategy = strategy
+	}
+}
+
+// WithLifecycleStateChangedFunc defines a callback that is invoked on every transition of the coordinator lifecycle.
+func WithLifecycleStateChangedFunc(lifecycleStateChangedFunc LifecycleStateChangedFunc) Option {
+	return func(opts *Options) {
+		opts.lifecycleStateChangedFunc = lifecycleStateChangedFunc
+	}
+}
+
 // WithQuorum defines a quorum, which is used to check the correct ledger state of the coordinator.
-// If no quorumGroups are given, the quorum is disabled.
+// If quorumEnabled is false, the quorum is disabled and the groups are ignored.
+// The configuration is validated by New, which returns an error if it is invalid.
 func WithQuorum(quorumEnabled bool, quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration) Option {
 	return func(opts *Options) {
-		if !quorumEnabled {
-			opts.quorum = nil
+		opts.quorumEnabled = quorumEnabled
+		opts.quorumGroups = quorumGroups
+		opts.quorumTimeout = timeout
+	}
+}
+
+// WithMaxParentsCount overwrites the maximum amount of parents of a block.
+// The amount of tips per checkpoint block and per milestone is derived from it.
+func WithMaxParentsCount(maxParentsCount int) Option {
+	return func(opts *Options) {
+		opts.maxParentsCount = maxParentsCount
+	}
+}
 
-			return
-		}
-		opts.quorum = newQuorum(quorumGroups, timeout)
+// WithSendBlockWithContextFunc defines a function used to send blocks, which can be cancelled via the context
+// passed to IssueMilestoneWithContext and IssueCheckpointWithContext. It replaces the SendBlockFunc given to New.
+func WithSendBlockWithContextFunc(sendBlockWithContextFunc SendBlockWithContextFunc) Option {
+	return func(opts *Options) {
+		opts.sendBlockWithContextFunc = sendBlockWithContextFunc
+	}
+}
+
+// WithQuorumCircuitBreaker defines after how many consecutive failures a quorum client
+// is skipped for the given cooldown, before a single request probes whether it recovered.
+// A failureThreshold of 0 disables the circuit breaker.
+func WithQuorumCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(opts *Options) {
+		opts.quorumCircuitBreakerThreshold = failureThreshold
+		opts.quorumCircuitBreakerCooldown = cooldown
+	}
+}
+
+// WithQuorumWeightThreshold defines the weight of agreeing clients needed to accept the merkle roots of a quorum group.
+// Single clients with different merkle roots are tolerated as long as the threshold is reached.
+// A weightThreshold of 0 requires all answering clients to agree.
+func WithQuorumWeightThreshold(weightThreshold int) Option {
+	return func(opts *Options) {
+		opts.quorumWeightThreshold = weightThreshold
+	}
+}
+
+// WithQuorumFailClosed defines whether the coordinator stops with a critical error if no quorum group answers in time.
+// By default the quorum fails open, so a quorum without answers is a non-critical error
+// and the next milestone is tried again.
+func WithQuorumFailClosed(failClosed bool) Option {
+	return func(opts *Options) {
+		opts.quorumFailClosed = failClosed
+	}
+}
+
+// WithQuorumClockSkewThreshold enables the check of the clocks of the clients in the quorum.
+// The milestone timestamp is compared with the Date header of the info API of every client that answered,
+// a clock skew above the threshold is logged as a warning, but doesn't fail the quorum.
+// The measured clock skew is part of the QuorumClientStatistic.
+func WithQuorumClockSkewThreshold(threshold time.Duration) Option {
+	return func(opts *Options) {
+		opts.quorumClockSkewThreshold = threshold
+	}
+}
+
+// WithQuorumCompression defines whether the requests to the clients in the quorum and their responses are compressed with gzip.
+// This reduces the bandwidth to remote clients, but the clients must accept gzip compressed requests.
+// The responses are asked for with gzip even if the transport disables the compression.
+func WithQuorumCompression(compression bool) Option {
+	return func(opts *Options) {
+		opts.quorumCompression = compression
+	}
+}
+
+// WithQuorumHealthPoll enables polling the info API of the clients in the quorum every interval,
+// so the error and the response time in the QuorumClientStatistic show the liveness of the clients between milestones.
+// The polling doesn't affect the quorum check of a milestone. It stops if the coordinator is shut down.
+// A value of 0 disables the polling.
+func WithQuorumHealthPoll(interval time.Duration) Option {
+	return func(opts *Options) {
+		opts.quorumHealthPollInterval = interval
 	}
 }
 
 // Option is a function setting a coordinator option.
 type Option func(opts *Options)
 
+// WithQuorumConcurrencyLimit defines the maximum amount of quorum requests in flight across all groups,
+// e.g. to not exhaust the file descriptors with large quorum groups.
+// Waiting for a free slot counts against the quorum timeout. A concurrencyLimit of 0 disables the limit.
+func WithQuorumConcurrencyLimit(concurrencyLimit int) Option {
+	return func(opts *Options) {
+		opts.quorumConcurrencyLimit = concurrencyLimit
+	}
+}
+
+// WithQuorumResultCache caches up to size successful quorum checks for the given duration,
+// so the quorum is not asked again if a milestone with the same inputs is computed repeatedly.
+// The cache is invalidated as soon as the milestone index advances. A size or ttl of 0 disables the cache.
+func WithQuorumResultCache(size int, ttl time.Duration) Option {
+	return func(opts *Options) {
+		opts.quorumResultCacheSize = size
+		opts.quorumResultCacheTTL = ttl
+	}
+}
+
+// WithQuorumTransport defines the transport shared by the quorum clients,
+// e.g. to tune the keep-alive and the connection pooling, so connections to the quorum nodes are reused.
+// Clients with a custom TLS configuration use a clone of the transport.
+// The timeouts of the quorum clients still apply to every request.
+func WithQuorumTransport(transport *http.Transport) Option {
+	return func(opts *Options) {
+		opts.quorumTransport = transport
+	}
+}
+
+// WithClock defines the clock used to determine the timestamps of milestones.
+func WithClock(clock Clock) Option {
+	return func(opts *Options) {
+		opts.clock = clock
+	}
+}
+
+// WithSendBlockRetry defines how often sending a milestone block is attempted
+// and how long to wait between the attempts, before a critical error is returned.
+func WithSendBlockRetry(attempts int, backoff time.Duration) Option {
+	return func(opts *Options) {
+		opts.sendBlockRetryAttempts = attempts
+		opts.sendBlockRetryBackoff = backoff
+	}
+}
+
+// WithStateWriteRetry defines how often writing the state file after a milestone was sent is attempted
+// and how long to wait between the attempts. If all attempts fail, the StatePersistFailed event is fired
+// with the new state and a critical error is returned.
+func WithStateWriteRetry(attempts int, backoff time.Duration) Option {
+	return func(opts *Options) {
+		opts.stateWriteRetryAttempts = attempts
+		opts.stateWriteRetryBackoff = backoff
+	}
+}
+
+// WithCrashRecovery enables the recovery of milestones that were sent to the network
+// before the coordinator crashed, but are missing in the state file.
+// The milestone is adopted into the state at startup instead of issuing a duplicate.
+func WithCrashRecovery(milestoneExistsFunc MilestoneExistsFunc) Option {
+	return func(opts *Options) {
+		opts.milestoneExistsFunc = milestoneExistsFunc
+	}
+}
+
+// WithBackPressureCacheTTL defines the duration the combined result of the back pressure functions is cached.
+// This avoids calling expensive back pressure functions for every checkpoint.
+func WithBackPressureCacheTTL(ttl time.Duration) Option {
+	return func(opts *Options) {
+		opts.backpressureCacheTTL = ttl
+	}
+}
+
+// WithBackPressureCacheBypassForMilestones defines whether the back pressure functions
+// are always called for milestones, instead of using the cached result.
+func WithBackPressureCacheBypassForMilestones(bypass bool) Option {
+	return func(opts *Options) {
+		opts.backpressureCacheBypassForMilestones = bypass
+	}
+}
+
+// WithBackPressureTimeout defines the maximum duration of a single back pressure function.
+// A function that doesn't return in time is treated as signaling congestion,
+// so a stalled check holds the issuance instead of blocking the coordinator.
+func WithBackPressureTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.backpressureTimeout = timeout
+	}
+}
+
+// WithSignerProvider defines the MilestoneSignerProvider used to sign the milestones.
+// It replaces the signer provider passed to New, e.g. to use a RemoteSigner.
+func WithSignerProvider(signerProvider MilestoneSignerProvider) Option {
+	return func(opts *Options) {
+		opts.signerProvider = signerProvider
+	}
+}
+
+// WithPreSendHook defines a hook that is called with every signed milestone block before it is sent to the network,
+// e.g. to check custom invariants. If the hook returns an error, the milestone is not sent
+// and the issuance fails with a critical error without changing the coordinator state.
+func WithPreSendHook(preSendHook PreSendHookFunc) Option {
+	return func(opts *Options) {
+		opts.preSendHook = preSendHook
+	}
+}
+
+// WithStateTransitionObserver defines an observer that is called with the state before and after every issued milestone,
+// just before the new state is persisted, e.g. to keep an audit log that allows reconstructing the milestone chain.
+// The observer is called while the milestone is issued, so it should not block.
+func WithStateTransitionObserver(observer StateTransitionObserverFunc) Option {
+	return func(opts *Options) {
+		opts.stateTransitionObserver = observer
+	}
+}
+
+// WithReceiptValidator defines a function that is called with every receipt of the migrator service
+// after the treasury transaction was embedded, e.g. to enforce custom migration invariants.
+// If the function returns an error, the milestone is not created and the issuance fails with a critical error.
+func WithReceiptValidator(receiptValidator ReceiptValidatorFunc) Option {
+	return func(opts *Options) {
+		opts.receiptValidator = receiptValidator
+	}
+}
+
+// WithPostSendHook defines a hook that is called synchronously after every milestone was sent to the network
+// and the coordinator state was stored, e.g. to write the milestone to an external audit log.
+// Errors of the hook are only logged and are not critical, because the milestone is already part of the network
+// and can't be revoked. The issuance of the next milestone is delayed until the hook returns.
+func WithPostSendHook(postSendHook PostSendHookFunc) Option {
+	return func(opts *Options) {
+		opts.postSendHook = postSendHook
+	}
+}
+
+// WithAdaptiveInterval enables an adaptive milestone interval between min and max, which replaces the fixed milestone interval.
+// The interval grows with the share of congestion signaled by the back pressure functions
+// during the recent milestones and shrinks back to min if the node is healthy again.
+func WithAdaptiveInterval(min time.Duration, max time.Duration) Option {
+	return func(opts *Options) {
+		opts.adaptiveIntervalMin = min
+		opts.adaptiveIntervalMax = max
+	}
+}
+
+// WithMilestoneMetadataFunc defines a function that returns operator-defined metadata, e.g. the software version,
+// which is embedded into every milestone if it is not empty.
+// The metadata must not exceed iotago.MaxMetadataLength, otherwise the milestone is not issued.
+func WithMilestoneMetadataFunc(milestoneMetadataFunc MilestoneMetadataFunc) Option {
+	return func(opts *Options) {
+		opts.milestoneMetadataFunc = milestoneMetadataFunc
+	}
+}
+
+// WithLatestMilestoneIndexFunc defines a function that returns the latest milestone index of the node.
+// Before a milestone is issued, the index is compared to the coordinator state to detect a stale state,
+// e.g. after a restore from a backup. If the node knows milestones the coordinator didn't issue,
+// or the node lags behind by more than one milestone, the issuance fails with a critical error.
+func WithLatestMilestoneIndexFunc(latestMilestoneIndexFunc LatestMilestoneIndexFunc) Option {
+	return func(opts *Options) {
+		opts.latestMilestoneIndexFunc = latestMilestoneIndexFunc
+	}
+}
+
+// WithTracerProvider enables the tracing of the milestone issuance with OpenTelemetry.
+// A span is started for the merkle root computation, the quorum check, the signing and the sending of every milestone.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(opts *Options) {
+		opts.tracerProvider = tracerProvider
+	}
+}
+
+// WithMetrics defines the Metrics used to record the durations and errors of the milestone issuance,
+// e.g. a PrometheusMetrics collector.
+func WithMetrics(metrics Metrics) Option {
+	return func(opts *Options) {
+		opts.metrics = metrics
+	}
+}
+
+// WithMinMilestoneInterval defines the minimum time between the timestamps of two milestones.
+// Milestones issued sooner after the previous milestone are rejected with a soft error,
+// independent of the interval the milestones are scheduled.
+func WithMinMilestoneInterval(minInterval time.Duration) Option {
+	return func(opts *Options) {
+		opts.minMilestoneInterval = minInterval
+	}
+}
+
+// WithMerkleComputeTimeout defines the maximum duration of the white flag computation of the merkle roots.
+// The computation is still not cancelled at shutdown, but a hung node can't block the issuance forever.
+// Exceeding the timeout is a critical error. A timeout of 0 disables the limit.
+func WithMerkleComputeTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.merkleComputeTimeout = timeout
+	}
+}
+
+// WithBlockSolidFunc defines the function used to check that the parents are solid, see IssueMilestoneStrict.
+func WithBlockSolidFunc(blockSolidFunc BlockSolidFunc) Option {
+	return func(opts *Options) {
+		opts.blockSolidFunc = blockSolidFunc
+	}
+}
+
+// WithBootstrapParentResolver enables the check that the parent of the first milestone contains the previous milestone,
+// if the network is not bootstrapped from genesis. The block of the previous milestone is passed to InitState
+// via LatestMilestoneInfo.BlockID, the check is skipped if the previous milestone ID is overridden.
+func WithBootstrapParentResolver(resolver BlockMilestoneIDFunc) Option {
+	return func(opts *Options) {
+		opts.bootstrapParentResolver = resolver
+	}
+}
+
+// WithHistorySize defines the amount of the latest issued milestones kept in memory, see Coordinator.History.
+func WithHistorySize(historySize int) Option {
+	return func(opts *Options) {
+		opts.historySize = historySize
+	}
+}
+
+// WithHistoryCompactAge defines the age after which milestones in the history are compacted,
+// only their index and timestamp are kept, to reduce the memory usage of long running coordinators.
+// The latest milestones younger than the given age keep all details, see Coordinator.History.
+// Soft errors are not kept in memory, they are only passed to the SoftError event, so there is nothing to compact.
+func WithHistoryCompactAge(historyCompactAge time.Duration) Option {
+	return func(opts *Options) {
+		opts.historyCompactAge = historyCompactAge
+	}
+}
+
+// WithBootstrapPreviousMilestoneID defines the milestone ID referenced by the first milestone at bootstrap,
+// instead of the milestone ID of the latest milestone of the node, e.g. to recover from a fork or network split.
+// The check that the previous milestone is not the genesis is skipped.
+func WithBootstrapPreviousMilestoneID(milestoneID iotago.MilestoneID) Option {
+	return func(opts *Options) {
+		opts.bootstrapPreviousMilestoneID = &milestoneID
+	}
+}
+
+// WithPendingProtocolParameterUpdate embeds the given protocol parameters in the milestone with the target index,
+// so the nodes switch to the new protocol parameters at the same milestone, e.g. for a coordinated protocol upgrade.
+// The target index must be in the future, otherwise New or InitState fail.
+func WithPendingProtocolParameterUpdate(targetIndex iotago.MilestoneIndex, params *iotago.ProtocolParameters) Option {
+	return func(opts *Options) {
+		opts.protocolParametersUpdateTargetIndex = targetIndex
+		opts.protocolParametersUpdate = params
+	}
+}
+
 // New creates a new coordinator instance.
 func New(
 	merkleRootFunc ComputeMilestoneMerkleRoots,
@@ -220,6 +879,57 @@ func New(
 	options.apply(defaultOptions...)
 	options.apply(opts...)
 
+	var q *quorum
+	if options.quorumEnabled {
+		var err error
+		if q, err = newQuorum(options.quorumGroups, options.quorumTimeout, options.quorumTransport); err != nil {
+			return nil, common.CriticalError(fmt.Errorf("failed to create coordinator quorum: %w", err))
+		}
+
+		q.applyOptions(options)
+
+		if err := q.validateWeightThreshold(); err != nil {
+			return nil, common.CriticalError(fmt.Errorf("failed to create coordinator quorum: %w", err))
+		}
+	}
+
+	if options.signerProvider != nil {
+		signerProvider = options.signerProvider
+	}
+
+	if signerProvider == nil {
+		return nil, common.CriticalError(errors.New("no milestone signer provider given"))
+	}
+
+	if rotatingSignerProvider, ok := signerProvider.(*RotatingSignerProvider); ok && options.latestMilestoneIndexFunc != nil {
+		// the next milestone needs enough keys, also after the next rotation of the signing keys
+		if err := rotatingSignerProvider.validateAround(options.latestMilestoneIndexFunc() + 1); err != nil {
+			return nil, common.CriticalError(fmt.Errorf("invalid signing key rotation schedule: %w", err))
+		}
+	}
+
+	if options.adaptiveIntervalMax > 0 && (options.adaptiveIntervalMin <= 0 || options.adaptiveIntervalMin > options.adaptiveIntervalMax) {
+		return nil, common.CriticalError(fmt.Errorf("invalid adaptive milestone interval, min: %v, max: %v", options.adaptiveIntervalMin, options.adaptiveIntervalMax))
+	}
+
+	if options.intervalJitter < 0 || options.intervalJitter >= 1 {
+		return nil, common.CriticalError(fmt.Errorf("invalid milestone interval jitter %v, must be in [0, 1)", options.intervalJitter))
+	}
+
+	var pendingProtocolParametersOpt *iotago.ProtocolParamsMilestoneOpt
+	if options.protocolParametersUpdate != nil || options.protocolParametersUpdateTargetIndex != 0 {
+		var err error
+		if pendingProtocolParametersOpt, err = newProtocolParametersMilestoneOpt(options.protocolParametersUpdateTargetIndex, options.protocolParametersUpdate); err != nil {
+			return nil, common.CriticalError(err)
+		}
+
+		if options.latestMilestoneIndexFunc != nil {
+			if err := checkProtocolParametersUpdateTarget(pendingProtocolParametersOpt, options.latestMilestoneIndexFunc()); err != nil {
+				return nil, common.CriticalError(err)
+			}
+		}
+	}
+
 	if migratorService != nil && treasuryOutputFunc == nil {
 		return nil, common.CriticalError(errors.New("migrator configured, but no treasury output fetch function provided"))
 	}
@@ -230,26 +940,64 @@ func New(
 		protoParamsFunc:    protoParamsFunc,
 		signerProvider:     signerProvider,
 		migratorService:    migratorService,
+		migratorEnabled:    migratorService != nil,
 		treasuryOutputFunc: treasuryOutputFunc,
 		sendBlockFunc:      sendBlockFunc,
 		opts:               options,
+		quorum:             q,
+		milestoneInterval:  options.milestoneInterval,
+
+		pendingProtocolParametersOpt: pendingProtocolParametersOpt,
 
 		Events: &Events{
-			IssuedCheckpointBlock: events.NewEvent(CheckpointCaller),
-			IssuedMilestone:       events.NewEvent(MilestoneCaller),
-			SoftError:             events.NewEvent(events.ErrorCaller),
-			QuorumFinished:        events.NewEvent(QuorumFinishedCaller),
+			IssuedCheckpointBlock:        events.NewEvent(CheckpointCaller),
+			IssuedCheckpointBlockDetails: events.NewEvent(CheckpointBlockDetailsCaller),
+			IssuedMilestone:              events.NewEvent(MilestoneCaller),
+			IssuingMilestone:             events.NewEvent(IssuingMilestoneCaller),
+			MilestoneSendFailed:          events.NewEvent(MilestoneSendFailedCaller),
+			StatePersistFailed:           events.NewEvent(StatePersistFailedCaller),
+			SoftError:                    events.NewEvent(events.ErrorCaller),
+			QuorumFinished:               events.NewEvent(QuorumFinishedCaller),
+			LifecycleStateChanged:        events.NewEvent(LifecycleStateChangedCaller),
 		},
 	}
 	result.WrappedLogger = logger.NewWrappedLogger(options.logger)
+	if options.adaptiveIntervalMax > 0 {
+		result.milestoneInterval = options.adaptiveIntervalMin
+		result.backPressureHistory = &backPressureHistory{}
+	}
+	if options.historySize > 0 {
+		result.history = newMilestoneHistory(options.historySize, options.historyCompactAge, options.clock)
+	}
+	result.tracer = trace.NewNoopTracerProvider().Tracer(tracerName)
+	if options.tracerProvider != nil {
+		result.tracer = options.tracerProvider.Tracer(tracerName)
+	}
+	result.shutdownCtx, result.shutdownCancel = context.WithCancel(context.Background())
+
+	if options.quorumHealthPollInterval > 0 {
+		go result.runQuorumHealthPoll(options.quorumHealthPollInterval)
+	}
 
 	return result, nil
 }
 
 // InitState loads an existing state file or bootstraps the network.
+// The state of a bootstrapped network is persisted immediately, so a restart before the first milestone
+// was issued resumes the bootstrapping without the bootstrap parameters.
+// The state can only be initialized once, further calls after a successful
+// initialization return ErrStateAlreadyInitialized. It is safe to call InitState concurrently.
 // All errors are critical.
 func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneIndex, latestMilestone *LatestMilestoneInfo) error {
 
+	coo.milestoneLock.Lock()
+	defer coo.triggerQueuedEvents()
+	defer coo.milestoneLock.Unlock()
+
+	if coo.state != nil {
+		return ErrStateAlreadyInitialized
+	}
+
 	_, err := os.Stat(coo.opts.stateFilePath)
 	stateFileExists := !os.IsNotExist(err)
 
@@ -268,8 +1016,13 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneInd
 		}
 
 		latestMilestoneID := iotago.MilestoneID{}
-		if startIndex != 1 {
+		switch {
+		case coo.opts.bootstrapPreviousMilestoneID != nil:
+			// the operator knows better, e.g. to recover from a fork or network split
+			latestMilestoneID = *coo.opts.bootstrapPreviousMilestoneID
+			coo.LogWarnf("OVERRIDING the previous milestone ID at bootstrap! index: %d, node milestone ID: %s, used milestone ID: %s", startIndex-1, iotago.EncodeHex(latestMilestone.MilestoneID[:]), iotago.EncodeHex(latestMilestoneID[:]))
 
+		case startIndex != 1:
 			if latestMilestone.MilestoneID == emptyMilestoneID {
 				return fmt.Errorf("previous milestone milestoneID should not be genesis")
 			}
@@ -278,252 +1031,1092 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneInd
 			latestMilestoneID = latestMilestone.MilestoneID
 		}
 
+		// the first milestone references the block of the previous milestone if it is known
+		latestMilestoneBlockID := iotago.EmptyBlockID()
+		if startIndex != 1 && coo.opts.bootstrapPreviousMilestoneID == nil {
+			latestMilestoneBlockID = latestMilestone.BlockID
+		}
+
 		// create a new coordinator state to bootstrap the network
 		state := &State{}
-		state.LatestMilestoneBlockID = iotago.EmptyBlockID()
+		state.LatestMilestoneBlockID = latestMilestoneBlockID
 		state.LatestMilestoneID = latestMilestoneID
 		state.LatestMilestoneIndex = startIndex - 1
-		state.LatestMilestoneTime = time.Now()
+		state.LatestMilestoneTime = coo.opts.clock.Now()
+		state.PendingBootstrap = true
+
+		if err := checkProtocolParametersUpdateTarget(coo.pendingProtocolParametersOpt, state.LatestMilestoneIndex); err != nil {
+			return err
+		}
+
+		// persist the state, so a restart before the first milestone doesn't need the bootstrap parameters again
+		if err := ioutils.WriteJSONToFile(coo.opts.stateFilePath, state, 0660); err != nil {
+			return fmt.Errorf("failed to write coordinator state file: %w", err)
+		}
 
 		coo.state = state
+		coo.publishState()
 		coo.bootstrapped = false
 
 		coo.LogInfof("bootstrapping coordinator at %d", startIndex)
+		coo.setLifecycleStateLocked(LifecycleStateBootstrapping)
 
 		return nil
 	}
 
+	if !stateFileExists && coo.opts.milestoneExistsFunc != nil {
+		// the coordinator may have crashed while issuing a milestone
+		if err := coo.restoreStateFile(); err != nil {
+			return err
+		}
+		stateFileExists = true
+	}
+
 	if !stateFileExists {
 		return fmt.Errorf("state file not found: %v", coo.opts.stateFilePath)
 	}
 
-	coo.state = &State{}
-	if err := ioutils.ReadJSONFromFile(coo.opts.stateFilePath, coo.state); err != nil {
+	state := &State{}
+	if err := ioutils.ReadJSONFromFile(coo.opts.stateFilePath, state); err != nil {
+		return err
+	}
+
+	if coo.opts.milestoneExistsFunc != nil {
+		if err := coo.recoverState(state); err != nil {
+			return err
+		}
+	}
+
+	if latestMilestone.Index != state.LatestMilestoneIndex {
+		return fmt.Errorf("previous milestone does not match latest milestone in node. previous: %d, INX: %d", state.LatestMilestoneIndex, latestMilestone.Index)
+	}
+
+	if err := checkProtocolParametersUpdateTarget(coo.pendingProtocolParametersOpt, state.LatestMilestoneIndex); err != nil {
 		return err
 	}
 
-	if latestMilestone.Index != coo.state.LatestMilestoneIndex {
-		return fmt.Errorf("previous milestone does not match latest milestone in node. previous: %d, INX: %d", coo.state.LatestMilestoneIndex, latestMilestone.Index)
+	coo.state = state
+	coo.publishState()
+
+	if state.PendingBootstrap {
+		coo.LogInfof("resuming bootstrapping of coordinator at %d", state.LatestMilestoneIndex+1)
+		coo.bootstrapped = false
+		coo.setLifecycleStateLocked(LifecycleStateBootstrapping)
+
+		return nil
 	}
 
 	coo.LogInfof("resuming coordinator at %d", latestMilestone.Index)
 
 	coo.bootstrapped = true
+	coo.setLifecycleStateLocked(LifecycleStateRunning)
 
 	return nil
 }
 
-// createAndSendMilestone creates a milestone, sends it to the network and stores a new coordinator state file.
-// Returns non-critical and critical errors.
-func (coo *Coordinator) createAndSendMilestone(parents iotago.BlockIDs, newMilestoneIndex iotago.MilestoneIndex, previousMilestoneID iotago.MilestoneID) error {
+// sendBlock sends a block to the network.
+// The context is only passed to the SendBlockWithContextFunc, the SendBlockFunc is not called at all if the context is already done.
+func (coo *Coordinator) sendBlock(ctx context.Context, block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+	if coo.opts.sendBlockWithContextFunc != nil {
+		return coo.opts.sendBlockWithContextFunc(ctx, block, msIndex...)
+	}
 
-	parents = parents.RemoveDupsAndSort()
+	if err := ctx.Err(); err != nil {
+		return iotago.EmptyBlockID(), err
+	}
 
-	// We have to set a timestamp for when we run the white-flag mutations due to the semantic validation.
-	// This should be exactly the same one used when issuing the milestone later on.
-	newMilestoneTimestamp := time.Now()
+	return coo.sendBlockFunc(block, msIndex...)
+}
+
+// sendMilestoneBlock sends a milestone block to the network.
+// Failed attempts are retried as configured via WithSendBlockRetry, the error of the last attempt is returned.
+// If an error is returned, it also returns whether the block may have reached the network anyway,
+// which is the case unless every attempt failed with ErrBlockNotSent.
+func (coo *Coordinator) sendMilestoneBlock(ctx context.Context, block *iotago.Block, msIndex iotago.MilestoneIndex) (iotago.BlockID, bool, error) {
+	attempts := coo.opts.sendBlockRetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	maybeSent := false
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = ctx.Err(); err != nil {
+			// no further attempt is made if the context is done
+			break
+		}
+
+		var blockID iotago.BlockID
+		blockID, err = coo.sendBlock(ctx, block, msIndex)
+		if err == nil {
+			return blockID, true, nil
+		}
+
+		if !errors.Is(err, ErrBlockNotSent) {
+			// e.g. the request timed out after the node accepted the block
+			maybeSent = true
+		}
+
+		if attempt >= attempts {
+			break
+		}
+
+		coo.LogWarnf("sending milestone %d failed, attempt %d/%d, retrying in %v, err: %s", msIndex, attempt, attempts, coo.opts.sendBlockRetryBackoff, err)
+
+		select {
+		case <-ctx.Done():
+			return iotago.EmptyBlockID(), maybeSent, ctx.Err()
+		case <-time.After(coo.opts.sendBlockRetryBackoff):
+		}
+	}
+
+	return iotago.EmptyBlockID(), maybeSent, err
+}
+
+// writeStateFile writes the current state to the state file after a milestone was sent.
+// Failed attempts are retried as configured via WithStateWriteRetry, the error of the last attempt is returned.
+// The retries are not aborted by the context, because the milestone was already sent.
+func (coo *Coordinator) writeStateFile(msIndex iotago.MilestoneIndex) error {
+	attempts := coo.opts.stateWriteRetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = ioutils.WriteJSONToFile(coo.opts.stateFilePath, coo.state, 0660); err == nil {
+			return nil
+		}
+
+		if attempt >= attempts {
+			return err
+		}
+
+		coo.LogWarnf("writing coordinator state file for milestone %d failed, attempt %d/%d, retrying in %v, err: %s", msIndex, attempt, attempts, coo.opts.stateWriteRetryBackoff, err)
+		time.Sleep(coo.opts.stateWriteRetryBackoff)
+	}
+}
+
+// issuanceAborted returns the context error as a soft error if the caller cancelled the issuance of the milestone.
+// A cancellation is a routine event, e.g. at shutdown, so it must not stop the coordinator.
+func issuanceAborted(ctx context.Context, index iotago.MilestoneIndex) error {
+	if err := ctx.Err(); err != nil {
+		return common.SoftError(fmt.Errorf("issuance of milestone %d aborted: %w", index, err))
+	}
+
+	return nil
+}
+
+// createAndSendMilestone creates a milestone, sends it to the network and stores a new coordinator state file.
+// The context is passed to the merkle root computation, the signing and to the function sending the milestone.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) createAndSendMilestone(ctx context.Context, parents iotago.BlockIDs, newMilestoneIndex iotago.MilestoneIndex, previousMilestoneID iotago.MilestoneID) (err error) {
+
+	parents = parents.RemoveDupsAndSort()
+
+	ctx, span := coo.startMilestoneSpan(ctx, spanNameIssueMilestone, newMilestoneIndex, len(parents))
+	defer func() { endSpan(span, err) }()
+
+	issuanceStart := time.Now()
+	timing := &IssuanceTiming{MilestoneIndex: newMilestoneIndex}
+	defer func() {
+		if err == nil {
+			coo.opts.metrics.ObserveIssuanceDuration(time.Since(issuanceStart))
+		}
+
+		timing.Total = time.Since(issuanceStart)
+		timing.Err = err
+		coo.setLastIssuanceTiming(timing)
+	}()
+
+	// an invalid milestone would only be detected by the node after it was signed
+	if maxParentsCount := coo.maxParentsCount(); len(parents) > maxParentsCount {
+		return common.SoftError(fmt.Errorf("%w: milestone %d has %d parents, maximum: %d", ErrTooManyParents, newMilestoneIndex, len(parents), maxParentsCount))
+	}
+
+	// We have to set a timestamp for when we run the white-flag mutations due to the semantic validation.
+	// This should be exactly the same one used when issuing the milestone later on.
+	newMilestoneTimestamp := coo.nextMilestoneTimestamp(coo.opts.clock.Now())
 
 	// compute merkle tree root
-	// we pass a background context here to not cancel the white-flag computation!
+	// callers pass a background context if the white-flag computation should not be cancelled,
 	// otherwise the coordinator could panic at shutdown.
-	merkleProof, err := coo.merkleRootFunc(context.Background(), newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID)
+	merkleStart := time.Now()
+	merkleCtx, merkleSpan := coo.startMilestoneSpan(ctx, spanNameMerkle, newMilestoneIndex, len(parents))
+	if coo.opts.merkleComputeTimeout > 0 {
+		var merkleCancel context.CancelFunc
+		merkleCtx, merkleCancel = context.WithTimeout(merkleCtx, coo.opts.merkleComputeTimeout)
+		defer merkleCancel()
+	}
+	merkleProof, err := coo.merkleRootFunc(merkleCtx, newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID)
+	if err != nil && errors.Is(merkleCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		err = fmt.Errorf("%w: no result after %v: %s", ErrMerkleComputeTimeout, coo.opts.merkleComputeTimeout, err)
+	}
+	endSpan(merkleSpan, err)
 	if err != nil {
+		if errAborted := issuanceAborted(ctx, newMilestoneIndex); errAborted != nil {
+			return errAborted
+		}
+
 		return common.CriticalError(fmt.Errorf("failed to compute white flag mutations: %w", err))
 	}
+	coo.observePhaseDuration(timing, IssuancePhaseMerkle, time.Since(merkleStart))
 
 	// ask the quorum for correct ledger state if enabled
-	if coo.opts.quorum != nil {
+	// the quorum could be replaced at runtime, the in-flight check uses the current one
+	if q := coo.currentQuorum(); q != nil {
 		ts := time.Now()
-		err := coo.opts.quorum.checkMerkleTreeHash(merkleProof, newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID, func(groupName string, entry *quorumGroupEntry, err error) {
-			coo.LogInfof("coordinator quorum group encountered an error, group: %s, baseURL: %s, err: %s", groupName, entry.stats.BaseURL, err)
+		_, quorumSpan := coo.startMilestoneSpan(ctx, spanNameQuorum, newMilestoneIndex, len(parents))
+		err := q.checkMerkleTreeHash(merkleProof, newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID, func(groupName string, entry *quorumGroupEntry, correlationID string, err error) {
+			if errors.Is(err, ErrQuorumClockSkew) {
+				coo.logWarnw("clock skew to a coordinator quorum client detected", logFieldMilestoneIndex, newMilestoneIndex, logFieldGroup, groupName, logFieldBaseURL, entry.stats.BaseURL, logFieldCorrelationID, correlationID, logFieldError, err)
+
+				return
+			}
+
+			if errors.Is(err, ErrQuorumMerkleTreeHashMismatch) {
+				// the other clients of the group outweighed the mismatch
+				coo.logWarnw("coordinator quorum client returned mismatching merkle roots", logFieldMilestoneIndex, newMilestoneIndex, logFieldGroup, groupName, logFieldBaseURL, entry.stats.BaseURL, logFieldCorrelationID, correlationID, logFieldError, err)
+
+				return
+			}
+
+			coo.logInfow("coordinator quorum group encountered an error", logFieldMilestoneIndex, newMilestoneIndex, logFieldGroup, groupName, logFieldBaseURL, entry.stats.BaseURL, logFieldCorrelationID, correlationID, logFieldError, err)
 		})
+		endSpan(quorumSpan, err)
 
 		duration := time.Since(ts)
-		coo.Events.QuorumFinished.Trigger(&QuorumFinishedResult{Duration: duration, Err: err})
+		quorumResult := &QuorumFinishedResult{Duration: duration, Err: err, Timestamp: coo.opts.clock.Now()}
+		coo.setLastQuorumResult(quorumResult)
+		coo.queueEvent(func() { coo.Events.QuorumFinished.Trigger(quorumResult) })
 
 		if err != nil {
 			// quorum failed => non-critical or critical error
-			coo.LogInfof("coordinator quorum failed after %v, err: %s", time.Since(ts).Truncate(time.Millisecond), err)
+			coo.logInfow("coordinator quorum failed", logFieldMilestoneIndex, newMilestoneIndex, logFieldDurationMs, durationMsField(duration), logFieldError, err)
 
 			return err
 		}
 
-		coo.LogInfof("coordinator quorum took %v", duration.Truncate(time.Millisecond))
+		coo.logInfow("coordinator quorum succeeded", logFieldMilestoneIndex, newMilestoneIndex, logFieldDurationMs, durationMsField(duration))
+		coo.observePhaseDuration(timing, IssuancePhaseQuorum, duration)
 	}
 
 	// get receipt data in case migrator is enabled
 	var receipt *iotago.ReceiptMilestoneOpt
-	if coo.migratorService != nil {
+	if coo.migratorService != nil && coo.migratorEnabled {
 		receipt = coo.migratorService.Receipt()
 		if receipt != nil {
-			if err := coo.migratorService.PersistState(true); err != nil {
-				return common.CriticalError(fmt.Errorf("unable to persist migrator state before send: %w", err))
-			}
-
 			currentTreasuryOutput, err := coo.treasuryOutputFunc()
 			if err != nil {
 				return common.CriticalError(fmt.Errorf("unable to fetch unspent treasury output: %w", err))
 			}
 
+			// the treasury output must already contain the migrations of the previous receipt
+			if err := coo.checkExpectedTreasuryOutput(currentTreasuryOutput); err != nil {
+				return common.CriticalError(err)
+			}
+
 			// embed treasury within the receipt
-			input := &iotago.TreasuryInput{}
-			copy(input[:], currentTreasuryOutput.MilestoneID[:])
-			output := &iotago.TreasuryOutput{Amount: currentTreasuryOutput.Amount - receipt.Sum()}
-			treasuryTx := &iotago.TreasuryTransaction{Input: input, Output: output}
-			receipt.Transaction = treasuryTx
-			receipt.SortFunds()
+			if err := embedTreasuryTransaction(receipt, currentTreasuryOutput); err != nil {
+				return common.CriticalError(err)
+			}
+
+			if coo.opts.receiptValidator != nil {
+				if err := coo.opts.receiptValidator(receipt, receipt.Transaction); err != nil {
+					return common.CriticalError(fmt.Errorf("receipt validator rejected the receipt of milestone %d: %w", newMilestoneIndex, err))
+				}
+			}
+
+			if err := coo.migratorService.PersistState(true); err != nil {
+				return common.CriticalError(fmt.Errorf("unable to persist migrator state before send: %w", err))
+			}
 		}
 	}
 
-	milestoneBlock, err := coo.createMilestone(newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, receipt, previousMilestoneID, merkleProof)
+	signingStart := time.Now()
+	signingCtx, signingSpan := coo.startMilestoneSpan(ctx, spanNameSigning, newMilestoneIndex, len(parents))
+	milestoneBlock, err := coo.createMilestone(signingCtx, newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, receipt, previousMilestoneID, merkleProof)
+	endSpan(signingSpan, err)
 	if err != nil {
+		if errAborted := issuanceAborted(ctx, newMilestoneIndex); errAborted != nil {
+			return errAborted
+		}
+
 		return common.CriticalError(fmt.Errorf("failed to create milestone: %w", err))
 	}
+	coo.observePhaseDuration(timing, IssuancePhaseSigning, time.Since(signingStart))
+
+	// the merkle roots are only valid for exactly the parents the white flag computation used
+	if err := verifyMilestoneParents(parents, milestoneBlock); err != nil {
+		return common.CriticalError(fmt.Errorf("milestone %d: %w", newMilestoneIndex, err))
+	}
 
 	milestoneID, err := milestoneBlock.Payload.(*iotago.Milestone).ID()
 	if err != nil {
 		return common.CriticalError(fmt.Errorf("failed to compute milestone ID: %w", err))
 	}
 
+	if coo.opts.preSendHook != nil {
+		if err := coo.opts.preSendHook(milestoneBlock, newMilestoneIndex); err != nil {
+			return common.CriticalError(fmt.Errorf("pre-send hook rejected milestone %d: %w", newMilestoneIndex, err))
+		}
+	}
+
 	// rename the coordinator state file to mark the state as invalid
-	if err := os.Rename(coo.opts.stateFilePath, fmt.Sprintf("%s_old", coo.opts.stateFilePath)); err != nil && !os.IsNotExist(err) {
+	if err := os.Rename(coo.opts.stateFilePath, coo.oldStateFilePath()); err != nil && !os.IsNotExist(err) {
 		return common.CriticalError(fmt.Errorf("unable to rename old coordinator state file: %w", err))
 	}
 
-	latestMilestoneBlockID, err := coo.sendBlockFunc(milestoneBlock, newMilestoneIndex)
+	coo.Events.IssuingMilestone.Trigger(newMilestoneIndex, milestoneID, parents)
+
+	// the state file was renamed once, retries only send the same milestone block again
+	sendStart := time.Now()
+	sendCtx, sendSpan := coo.startMilestoneSpan(ctx, spanNameSend, newMilestoneIndex, len(parents))
+	latestMilestoneBlockID, maybeSent, err := coo.sendMilestoneBlock(sendCtx, milestoneBlock, newMilestoneIndex)
+	endSpan(sendSpan, err)
 	if err != nil {
+		sendErr := err
+		coo.queueEvent(func() { coo.Events.MilestoneSendFailed.Trigger(newMilestoneIndex, sendErr) })
+
+		if maybeSent {
+			// issuing another milestone with the same index would create a conflicting milestone,
+			// so the old state file is not restored and the index is only used again after it was checked whether the milestone exists.
+			coo.unconfirmedMilestoneIndex = newMilestoneIndex
+
+			if errAborted := issuanceAborted(ctx, newMilestoneIndex); errAborted != nil {
+				return errAborted
+			}
+
+			return common.CriticalError(fmt.Errorf("failed to send milestone %d, it may have been issued anyway: %w", newMilestoneIndex, err))
+		}
+
+		// the milestone was certainly not sent, so the old state is still valid and a restart can resume with it
+		if errRestore := os.Rename(coo.oldStateFilePath(), coo.opts.stateFilePath); errRestore != nil && !os.IsNotExist(errRestore) {
+			return common.CriticalError(fmt.Errorf("failed to send milestone: %w, unable to restore coordinator state file: %s", err, errRestore))
+		}
+
+		if errAborted := issuanceAborted(ctx, newMilestoneIndex); errAborted != nil {
+			return errAborted
+		}
+
 		return common.CriticalError(fmt.Errorf("failed to send milestone: %w", err))
 	}
+	coo.observePhaseDuration(timing, IssuancePhaseSend, time.Since(sendStart))
 
 	if coo.migratorService != nil && receipt != nil {
 		if err := coo.migratorService.PersistState(false); err != nil {
 			return common.CriticalError(fmt.Errorf("unable to persist migrator state after send: %w", err))
 		}
+
+		coo.expectedTreasuryOutput = &LatestTreasuryOutput{
+			MilestoneID: milestoneID,
+			Amount:      receipt.Transaction.Output.Amount,
+		}
 	}
 
+	previousState := *coo.state
+
 	// always reference the last milestone directly to speed up syncing
 	coo.state.LatestMilestoneBlockID = latestMilestoneBlockID
 	coo.state.LatestMilestoneID = milestoneID
 	coo.state.LatestMilestoneIndex = newMilestoneIndex
 	coo.state.LatestMilestoneTime = newMilestoneTimestamp
+	coo.state.PendingBootstrap = false
+	coo.publishState()
+
+	if coo.opts.stateTransitionObserver != nil {
+		coo.opts.stateTransitionObserver(previousState, *coo.state)
+	}
+
+	stateWriteStart := time.Now()
+	if err := coo.writeStateFile(newMilestoneIndex); err != nil {
+		// the milestone is already part of the network, so the new state must not get lost
+		state, persistErr := *coo.state, err
+		coo.queueEvent(func() { coo.Events.StatePersistFailed.Trigger(state, persistErr) })
 
-	if err := ioutils.WriteJSONToFile(coo.opts.stateFilePath, coo.state, 0660); err != nil {
 		return common.CriticalError(fmt.Errorf("failed to update coordinator state file: %w", err))
 	}
+	coo.observePhaseDuration(timing, IssuancePhaseStateWrite, time.Since(stateWriteStart))
+
+	if err := coo.rotateStateBackups(); err != nil {
+		// the new state was written, so the coordinator can continue without the backup
+		coo.LogWarnf("unable to rotate coordinator state backups: %s", err)
+	}
 
-	coo.Events.IssuedMilestone.Trigger(coo.state.LatestMilestoneIndex, coo.state.LatestMilestoneID, coo.state.LatestMilestoneBlockID)
+	// a new milestone resets the checkpoints
+	coo.checkpointsSinceMilestone.Store(0)
+	coo.issuedSinceStart.Add(1)
+
+	if coo.pendingProtocolParametersOpt != nil && coo.pendingProtocolParametersOpt.TargetMilestoneIndex == newMilestoneIndex {
+		coo.LogInfof("protocol parameters update to version %d embedded in milestone %d", coo.pendingProtocolParametersOpt.ProtocolVersion, newMilestoneIndex)
+		coo.pendingProtocolParametersOpt = nil
+	}
+
+	if coo.opts.postSendHook != nil {
+		// the milestone is already sent, so errors of the hook can't abort the issuance
+		if err := coo.opts.postSendHook(coo.state.LatestMilestoneIndex, coo.state.LatestMilestoneID, coo.state.LatestMilestoneBlockID); err != nil {
+			coo.LogWarnf("post-send hook failed for milestone %d: %s", coo.state.LatestMilestoneIndex, err)
+		}
+	}
+
+	coo.logInfow("milestone issued",
+		logFieldMilestoneIndex, coo.state.LatestMilestoneIndex,
+		logFieldMilestoneID, milestoneIDField(coo.state.LatestMilestoneID),
+		logFieldBlockID, coo.state.LatestMilestoneBlockID.ToHex(),
+		logFieldDurationMs, durationMsField(time.Since(issuanceStart)),
+	)
+
+	if coo.history != nil {
+		coo.history.add(MilestoneRecord{
+			Index:        coo.state.LatestMilestoneIndex,
+			MilestoneID:  coo.state.LatestMilestoneID,
+			BlockID:      coo.state.LatestMilestoneBlockID,
+			Timestamp:    coo.state.LatestMilestoneTime,
+			Duration:     time.Since(issuanceStart),
+			ParentsCount: len(parents),
+			HasReceipt:   receipt != nil,
+		})
+	}
+
+	issuedIndex, issuedMilestoneID, issuedBlockID := coo.state.LatestMilestoneIndex, coo.state.LatestMilestoneID, coo.state.LatestMilestoneBlockID
+	coo.queueEvent(func() { coo.Events.IssuedMilestone.Trigger(issuedIndex, issuedMilestoneID, issuedBlockID) })
 
 	return nil
 }
 
+// nextMilestoneTimestamp returns the timestamp of the next milestone.
+// Milestone timestamps have a resolution of seconds and must be strictly increasing,
+// so the timestamp is bumped to one second after the previous milestone if necessary.
+// The first milestone of a network has no previous milestone issued by this coordinator.
+func (coo *Coordinator) nextMilestoneTimestamp(now time.Time) time.Time {
+	if !coo.bootstrapped {
+		return now
+	}
+
+	if minTimestamp := coo.state.LatestMilestoneTime.Unix() + 1; now.Unix() < minTimestamp {
+		coo.LogInfof("bumping milestone timestamp from %d to %d, the previous milestone has timestamp %d", now.Unix(), minTimestamp, coo.state.LatestMilestoneTime.Unix())
+
+		return time.Unix(minTimestamp, 0)
+	}
+
+	return now
+}
+
 // Bootstrap creates the first milestone, if the network was not bootstrapped yet.
+// If the coordinator is read-only, a non-critical error is returned.
 // Returns critical errors.
 func (coo *Coordinator) Bootstrap() (iotago.BlockID, error) {
 
 	coo.milestoneLock.Lock()
+	defer coo.triggerQueuedEvents()
 	defer coo.milestoneLock.Unlock()
 
 	if !coo.bootstrapped {
+		if coo.LifecycleState() == LifecycleStateReadOnly {
+			return iotago.EmptyBlockID(), common.SoftError(ErrCoordinatorReadOnly)
+		}
+
+		adopted, err := coo.resolveUnconfirmedMilestone()
+		if err != nil {
+			return iotago.EmptyBlockID(), common.CriticalError(err)
+		}
+
+		if adopted {
+			// the first milestone was issued before sending it failed
+			coo.bootstrapped = true
+			coo.setLifecycleStateLocked(LifecycleStateRunning)
+
+			return coo.state.LatestMilestoneBlockID, nil
+		}
+
 		// create first milestone to bootstrap the network
 		// only one parent references the last known milestone or NullBlockID if startIndex = 1 (see InitState)
-		err := coo.createAndSendMilestone(iotago.BlockIDs{coo.state.LatestMilestoneBlockID}, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID)
+		parents, err := coo.milestoneParents(nil)
 		if err != nil {
-			// creating milestone failed => always a critical error at bootstrap
 			return iotago.EmptyBlockID(), common.CriticalError(err)
 		}
 
+		if err := coo.verifyBootstrapParent(); err != nil {
+			err = common.CriticalError(err)
+			coo.setLifecycleStateLocked(LifecycleStateReadOnly)
+			coo.observeIssuanceError(err)
+
+			return iotago.EmptyBlockID(), err
+		}
+
+		// we pass a background context here to not cancel the white-flag computation!
+		if err := coo.createAndSendMilestone(context.Background(), parents, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID); err != nil {
+			// creating milestone failed => always a critical error at bootstrap
+			err = common.CriticalError(err)
+			coo.setLifecycleStateLocked(LifecycleStateReadOnly)
+			coo.observeIssuanceError(err)
+
+			return iotago.EmptyBlockID(), err
+		}
+
 		coo.bootstrapped = true
+		coo.setLifecycleStateLocked(LifecycleStateRunning)
 	}
 
 	return coo.state.LatestMilestoneBlockID, nil
 }
 
+// verifyBootstrapParent checks that the parent of the first milestone contains the previous milestone,
+// if the network is not bootstrapped from genesis and a resolver was configured.
+func (coo *Coordinator) verifyBootstrapParent() error {
+	if coo.opts.bootstrapParentResolver == nil || coo.opts.bootstrapPreviousMilestoneID != nil || coo.state.LatestMilestoneIndex == 0 {
+		return nil
+	}
+
+	parentBlockID := coo.state.LatestMilestoneBlockID
+	milestoneID, found, err := coo.opts.bootstrapParentResolver(parentBlockID)
+	if err != nil {
+		return fmt.Errorf("unable to resolve the milestone of bootstrap parent %s: %w", parentBlockID.ToHex(), err)
+	}
+
+	if !found {
+		return fmt.Errorf("%w: parent %s doesn't contain a milestone, expected: %s", ErrBootstrapParentMismatch, parentBlockID.ToHex(), iotago.EncodeHex(coo.state.LatestMilestoneID[:]))
+	}
+
+	if milestoneID != coo.state.LatestMilestoneID {
+		return fmt.Errorf("%w: parent %s contains milestone %s, expected: %s", ErrBootstrapParentMismatch, parentBlockID.ToHex(), iotago.EncodeHex(milestoneID[:]), iotago.EncodeHex(coo.state.LatestMilestoneID[:]))
+	}
+
+	return nil
+}
+
 // IssueCheckpoint tries to create and send a "checkpoint" to the network.
 // a checkpoint can contain multiple chained blocks to reference big parts of the unreferenced cone.
 // this is done to keep the confirmation rate as high as possible, even if there is an attack ongoing.
 // new checkpoints always reference the last checkpoint or the last milestone if it is the first checkpoint after a new milestone.
+// with CheckpointStrategyFanOut the blocks of a checkpoint are not chained, but all reference the last checkpoint.
+// the ID of the last sent block is returned in both cases.
 func (coo *Coordinator) IssueCheckpoint(checkpointIndex int, lastCheckpointBlockID iotago.BlockID, tips iotago.BlockIDs) (iotago.BlockID, error) {
+	return coo.IssueCheckpointWithContext(context.Background(), checkpointIndex, lastCheckpointBlockID, tips)
+}
+
+// IssueCheckpointWithContext tries to create and send a "checkpoint" to the network.
+// The context is passed to the function sending the checkpoint blocks.
+// If the context is done before all blocks of the checkpoint were sent, the ID of the last sent block
+// (or lastCheckpointBlockID if none was sent) is returned together with the wrapped context error,
+// so the caller can resume from there.
+// See IssueCheckpoint for details.
+func (coo *Coordinator) IssueCheckpointWithContext(ctx context.Context, checkpointIndex int, lastCheckpointBlockID iotago.BlockID, tips iotago.BlockIDs) (iotago.BlockID, error) {
+	blockID, err := coo.issueCheckpoint(ctx, checkpointIndex, lastCheckpointBlockID, tips)
+	coo.triggerSoftErrorEvent(err)
+
+	return blockID, err
+}
+
+// issueCheckpoint creates and sends a checkpoint while the milestone lock is held.
+// See IssueCheckpointWithContext for details.
+func (coo *Coordinator) issueCheckpoint(ctx context.Context, checkpointIndex int, lastCheckpointBlockID iotago.BlockID, tips iotago.BlockIDs) (iotago.BlockID, error) {
 
 	if len(tips) == 0 {
 		return iotago.EmptyBlockID(), ErrNoTipsGiven
 	}
 
 	coo.milestoneLock.Lock()
+	defer coo.triggerQueuedEvents()
 	defer coo.milestoneLock.Unlock()
 
-	if !coo.isNodeSynced() {
+	if coo.shutdown.Load() {
+		return iotago.EmptyBlockID(), common.SoftError(ErrCoordinatorShutdown)
+	}
+
+	if coo.IsPaused() {
+		return iotago.EmptyBlockID(), common.SoftError(ErrCoordinatorPaused)
+	}
+
+	if coo.LifecycleState() == LifecycleStateReadOnly {
+		return iotago.EmptyBlockID(), common.SoftError(ErrCoordinatorReadOnly)
+	}
+
+	if !coo.NodeSynced() {
 		return iotago.EmptyBlockID(), common.SoftError(common.ErrNodeNotSynced)
 	}
 
+	if coo.opts.maxCheckpointsPerInterval > 0 && int(coo.checkpointsSinceMilestone.Load()) >= coo.opts.maxCheckpointsPerInterval {
+		return iotago.EmptyBlockID(), common.SoftError(fmt.Errorf("%w: %d checkpoints since the last milestone", ErrCheckpointLimitReached, coo.opts.maxCheckpointsPerInterval))
+	}
+
 	// check whether we should hold issuing checkpoints
 	// if the node is currently under a lot of load
-	if coo.checkBackPressureFunctions() {
-		return iotago.EmptyBlockID(), common.SoftError(ErrNodeLoadTooHigh)
+	if name := coo.checkBackPressureFunctions(false); name != "" {
+		coo.logInfow("holding issuance, back pressure signaled", logFieldBlockType, "checkpoint", logFieldBackPressure, name)
+
+		return iotago.EmptyBlockID(), newTriggeredSoftError(fmt.Errorf("%w: back pressure signaled by %s", ErrNodeLoadTooHigh, name))
 	}
 
-	// maximum 8 parents per block (7 tips + last checkpoint blockID)
-	checkpointsNumber := int(math.Ceil(float64(len(tips)) / 7.0))
+	// one parent of every checkpoint block is reserved for the last checkpoint blockID
+	tipsPerCheckpoint := coo.maxParentsCount() - 1
+	if tipsPerCheckpoint < 1 {
+		return iotago.EmptyBlockID(), common.SoftError(fmt.Errorf("%w: maximum of %d parents leaves no room for tips", ErrTooManyParents, coo.maxParentsCount()))
+	}
+
+	// the tips are sorted once for the whole batch, so the tips of every checkpoint block are already sorted,
+	// and duplicated tips don't take up parents of several checkpoint blocks.
+	sortedTips := sortedUniqueBlockIDs(tips)
+	checkpointsNumber := (len(sortedTips) + tipsPerCheckpoint - 1) / tipsPerCheckpoint
+
+	protoParams := coo.protoParamsFunc()
 
-	// issue several checkpoints until all tips are used
+	// the block referenced by every checkpoint block if the blocks fan out
+	fanOutBlockID := lastCheckpointBlockID
+
+	// issue several checkpoints until all tips are used.
+	// chained checkpoint blocks need to be sent strictly one after another, since every block references the previous one,
+	// and its block ID is only known after it was sent (the node may do the proof of work and set the nonce).
 	for i := 0; i < checkpointsNumber; i++ {
-		tipStart := i * 7
-		tipEnd := tipStart + 7
+		tipStart := i * tipsPerCheckpoint
+		tipEnd := tipStart + tipsPerCheckpoint
+
+		if tipEnd > len(sortedTips) {
+			tipEnd = len(sortedTips)
+		}
 
-		if tipEnd > len(tips) {
-			tipEnd = len(tips)
+		if err := ctx.Err(); err != nil {
+			return lastCheckpointBlockID, common.SoftError(fmt.Errorf("checkpoint cancelled after %d of %d blocks: %w", i, checkpointsNumber, err))
 		}
 
-		parents := iotago.BlockIDs{lastCheckpointBlockID}
-		parents = append(parents, tips[tipStart:tipEnd]...)
-		parents = parents.RemoveDupsAndSort()
+		parentBlockID := lastCheckpointBlockID
+		if coo.opts.checkpointStrategy == CheckpointStrategyFanOut {
+			parentBlockID = fanOutBlockID
+		}
 
-		block, err := coo.createCheckpoint(parents)
+		block, err := coo.createCheckpoint(protoParams, checkpointParents(parentBlockID, sortedTips[tipStart:tipEnd]))
 		if err != nil {
 			return iotago.EmptyBlockID(), common.SoftError(fmt.Errorf("failed to create checkPoint: %w", err))
 		}
 
-		blockID, err := coo.sendBlockFunc(block)
+		blockID, err := coo.sendBlock(ctx, block)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return lastCheckpointBlockID, common.SoftError(fmt.Errorf("checkpoint cancelled after %d of %d blocks: %w", i, checkpointsNumber, ctxErr))
+			}
+
 			return iotago.EmptyBlockID(), common.SoftError(fmt.Errorf("failed to send checkPoint: %w", err))
 		}
 
 		lastCheckpointBlockID = blockID
 
-		coo.Events.IssuedCheckpointBlock.Trigger(checkpointIndex, i, checkpointsNumber, lastCheckpointBlockID)
+		blockIndex, checkpointBlockInfo := i, &IssuedCheckpointBlockInfo{
+			CheckpointIndex: checkpointIndex,
+			BlockIndex:      i,
+			BlocksTotal:     checkpointsNumber,
+			BlockID:         lastCheckpointBlockID,
+			Parents:         block.Parents,
+		}
+		coo.queueEvent(func() {
+			coo.Events.IssuedCheckpointBlock.Trigger(checkpointIndex, blockIndex, checkpointsNumber, checkpointBlockInfo.BlockID)
+			coo.Events.IssuedCheckpointBlockDetails.Trigger(checkpointBlockInfo)
+		})
 	}
 
+	coo.checkpointsSinceMilestone.Add(1)
+
 	return lastCheckpointBlockID, nil
 }
 
 // IssueMilestone creates the next milestone.
 // Returns non-critical and critical errors.
 func (coo *Coordinator) IssueMilestone(parents iotago.BlockIDs) (iotago.BlockID, error) {
+	// we pass a background context here to not cancel the white-flag computation!
+	// otherwise the coordinator could panic at shutdown.
+	return coo.IssueMilestoneWithContext(context.Background(), parents)
+}
+
+// IssueMilestoneWithContext creates the next milestone.
+// The context is passed to the merkle root computation and to the function sending the milestone.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) IssueMilestoneWithContext(ctx context.Context, parents iotago.BlockIDs) (iotago.BlockID, error) {
+	blockID, _, err := coo.issueAndObserveMilestone(ctx, parents, milestoneIssuanceChecks{})
+
+	return blockID, err
+}
+
+// IssueMilestoneWithID creates the next milestone like IssueMilestone.
+// Returns the ID of the milestone block and the ID of the milestone.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) IssueMilestoneWithID(parents iotago.BlockIDs) (iotago.BlockID, iotago.MilestoneID, error) {
+	// we pass a background context here to not cancel the white-flag computation!
+	// otherwise the coordinator could panic at shutdown.
+	return coo.issueAndObserveMilestone(context.Background(), parents, milestoneIssuanceChecks{})
+}
+
+// IssueMilestoneStrict creates the next milestone like IssueMilestone.
+// If requireSolid is true, all given parents must be solid in the node, which is checked via the BlockSolidFunc,
+// otherwise a soft error listing the parents that are not solid is returned.
+// This prevents a milestone referencing blocks the node didn't solidify yet, which could not be confirmed.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) IssueMilestoneStrict(parents iotago.BlockIDs, requireSolid bool) (iotago.BlockID, error) {
+	// we pass a background context here to not cancel the white-flag computation!
+	// otherwise the coordinator could panic at shutdown.
+	blockID, _, err := coo.issueAndObserveMilestone(context.Background(), parents, milestoneIssuanceChecks{requireSolid: requireSolid})
+
+	return blockID, err
+}
+
+// IssueMilestoneExpecting creates the next milestone like IssueMilestone,
+// if the ID of the previous milestone in the coordinator state matches the ID the caller expects.
+// Otherwise a critical error is returned without issuing a milestone,
+// because the views of the caller and the coordinator on the milestone chain diverged (split-brain).
+// Returns non-critical and critical errors.
+func (coo *Coordinator) IssueMilestoneExpecting(parents iotago.BlockIDs, expectedPreviousMilestoneID iotago.MilestoneID) (iotago.BlockID, error) {
+	// we pass a background context here to not cancel the white-flag computation!
+	// otherwise the coordinator could panic at shutdown.
+	blockID, _, err := coo.issueAndObserveMilestone(context.Background(), parents, milestoneIssuanceChecks{expectedPreviousMilestoneID: &expectedPreviousMilestoneID})
+
+	return blockID, err
+}
+
+// IssueKeepAliveMilestone creates the next milestone with the previous milestone block as its only parent,
+// e.g. to keep the network alive during idle periods without selecting tips.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) IssueKeepAliveMilestone() (iotago.BlockID, error) {
+	// without tips, the previous milestone block is the only parent, see milestoneParents.
+	// we pass a background context here to not cancel the white-flag computation!
+	// otherwise the coordinator could panic at shutdown.
+	blockID, _, err := coo.issueAndObserveMilestone(context.Background(), nil, milestoneIssuanceChecks{})
+
+	return blockID, err
+}
+
+// milestoneIssuanceChecks are the optional checks before a milestone is issued.
+type milestoneIssuanceChecks struct {
+	// whether the given parents must be solid.
+	requireSolid bool
+	// the ID of the previous milestone the caller expects, nil if it is not checked.
+	expectedPreviousMilestoneID *iotago.MilestoneID
+}
+
+// issueAndObserveMilestone creates the next milestone and records the outcome of the issuance.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) issueAndObserveMilestone(ctx context.Context, parents iotago.BlockIDs, checks milestoneIssuanceChecks) (iotago.BlockID, iotago.MilestoneID, error) {
+	blockID, milestoneID, err := coo.issueMilestone(ctx, parents, checks)
+	coo.updateLifecycleStateAfterIssuance(err)
+	coo.observeIssuanceError(err)
+	coo.triggerSoftErrorEvent(err)
+
+	if err := common.IsSoftError(err); err != nil {
+		coo.logWarnw("milestone issuance failed with a soft error", logFieldMilestoneIndex, coo.LatestMilestoneIndex()+1, logFieldError, err)
+	}
+
+	return blockID, milestoneID, err
+}
+
+// issueMilestone creates the next milestone and returns the IDs of its block and of the milestone.
+// The optional checks are done before the milestone is computed.
+// Returns non-critical and critical errors.
+func (coo *Coordinator) issueMilestone(ctx context.Context, parents iotago.BlockIDs, checks milestoneIssuanceChecks) (iotago.BlockID, iotago.MilestoneID, error) {
 
 	coo.milestoneLock.Lock()
+	defer coo.triggerQueuedEvents()
 	defer coo.milestoneLock.Unlock()
 
-	if !coo.isNodeSynced() {
+	if coo.shutdown.Load() {
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, common.SoftError(ErrCoordinatorShutdown)
+	}
+
+	if coo.IsPaused() {
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, common.SoftError(ErrCoordinatorPaused)
+	}
+
+	if coo.LifecycleState() == LifecycleStateReadOnly {
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, common.SoftError(ErrCoordinatorReadOnly)
+	}
+
+	if !coo.NodeSynced() {
 		// return a non-critical error to not kill the database
-		return iotago.EmptyBlockID(), common.SoftError(common.ErrNodeNotSynced)
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, common.SoftError(common.ErrNodeNotSynced)
+	}
+
+	if _, err := coo.resolveUnconfirmedMilestone(); err != nil {
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, err
+	}
+
+	if err := coo.checkMilestoneIndexGap(); err != nil {
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, err
+	}
+
+	if checks.expectedPreviousMilestoneID != nil && *checks.expectedPreviousMilestoneID != coo.state.LatestMilestoneID {
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, common.CriticalError(fmt.Errorf("%w: expected: %s, coordinator state: %s", ErrPreviousMilestoneIDMismatch, checks.expectedPreviousMilestoneID.ToHex(), coo.state.LatestMilestoneID.ToHex()))
+	}
+
+	if coo.opts.minMilestoneInterval > 0 {
+		if sinceLatestMilestone := coo.opts.clock.Now().Sub(coo.state.LatestMilestoneTime); sinceLatestMilestone < coo.opts.minMilestoneInterval {
+			return iotago.EmptyBlockID(), iotago.MilestoneID{}, common.SoftError(fmt.Errorf("%w: %v after the previous milestone, minimum interval: %v", ErrMilestoneTooEarly, sinceLatestMilestone, coo.opts.minMilestoneInterval))
+		}
 	}
 
 	// check whether we should hold issuing miletones
 	// if the node is currently under a lot of load
-	if coo.checkBackPressureFunctions() {
-		return iotago.EmptyBlockID(), common.SoftError(ErrNodeLoadTooHigh)
+	name := coo.checkBackPressureFunctions(coo.opts.backpressureCacheBypassForMilestones)
+	coo.adaptInterval(name != "")
+
+	if name != "" {
+		coo.logInfow("holding issuance, back pressure signaled", logFieldBlockType, "milestone", logFieldBackPressure, name)
+
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, newTriggeredSoftError(fmt.Errorf("%w: back pressure signaled by %s", ErrNodeLoadTooHigh, name))
 	}
 
-	if err := coo.createAndSendMilestone(parents, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID); err != nil {
+	if checks.requireSolid {
+		if err := coo.checkParentsSolid(parents); err != nil {
+			return iotago.EmptyBlockID(), iotago.MilestoneID{}, err
+		}
+	}
+
+	// always reference the previous milestone
+	parents, err := coo.milestoneParents(parents)
+	if err != nil {
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, common.SoftError(err)
+	}
+
+	if err := coo.createAndSendMilestone(ctx, parents, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID); err != nil {
 		// creating milestone failed => non-critical or critical error
-		return iotago.EmptyBlockID(), err
+		return iotago.EmptyBlockID(), iotago.MilestoneID{}, err
 	}
 
-	return coo.state.LatestMilestoneBlockID, nil
+	return coo.state.LatestMilestoneBlockID, coo.state.LatestMilestoneID, nil
+}
+
+// ComputeNextMilestone computes the next milestone without sending it to the network.
+// The state of the coordinator, the state file and the migrator are not modified,
+// therefore the milestone never contains a receipt and the quorum is not asked.
+// This can be used for monitoring and pre-flight validation.
+func (coo *Coordinator) ComputeNextMilestone(parents iotago.BlockIDs) (*iotago.Block, *MilestoneMerkleRoots, error) {
+
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	if coo.state == nil {
+		return nil, nil, ErrStateNotInitialized
+	}
+
+	// always reference the previous milestone
+	parents, err := coo.milestoneParents(parents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newMilestoneIndex := coo.state.LatestMilestoneIndex + 1
+	newMilestoneTimestamp := uint32(coo.nextMilestoneTimestamp(coo.opts.clock.Now()).Unix())
+
+	merkleProof, err := coo.merkleRootFunc(context.Background(), newMilestoneIndex, newMilestoneTimestamp, parents, coo.state.LatestMilestoneID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute white flag mutations: %w", err)
+	}
+
+	milestoneBlock, err := coo.createMilestone(context.Background(), newMilestoneIndex, newMilestoneTimestamp, parents, nil, coo.state.LatestMilestoneID, merkleProof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create milestone: %w", err)
+	}
+
+	return milestoneBlock, merkleProof, nil
+}
+
+// checkMilestoneIndexGap compares the latest milestone index of the node with the coordinator state.
+// The node may lag behind by one milestone, if the previous milestone was not received yet.
+// Returns ErrMilestoneIndexGap if issuing the next milestone would create a duplicate or a gap.
+// A node that lags behind is a non-critical error, because it may catch up, like a node that is not synced.
+// A node that knows milestones the coordinator didn't issue is a critical error.
+func (coo *Coordinator) checkMilestoneIndexGap() error {
+	if coo.opts.latestMilestoneIndexFunc == nil {
+		return nil
+	}
+
+	nodeMilestoneIndex := coo.opts.latestMilestoneIndexFunc()
+	cooMilestoneIndex := coo.state.LatestMilestoneIndex
+
+	if nodeMilestoneIndex > cooMilestoneIndex {
+		return common.CriticalError(fmt.Errorf("%w: node knows milestone %d, but the latest milestone of the coordinator is %d, the coordinator state may be stale", ErrMilestoneIndexGap, nodeMilestoneIndex, cooMilestoneIndex))
+	}
+
+	if nodeMilestoneIndex+1 < cooMilestoneIndex {
+		return common.SoftError(fmt.Errorf("%w: latest milestone of the node is %d, but the latest milestone of the coordinator is %d", ErrMilestoneIndexGap, nodeMilestoneIndex, cooMilestoneIndex))
+	}
+
+	return nil
+}
+
+// maxParentsCount returns the maximum amount of parents of a block.
+// The protocol parameters don't define the maximum, so the protocol constant is used if it was not overwritten.
+func (coo *Coordinator) maxParentsCount() int {
+	if coo.opts.maxParentsCount > 0 {
+		return coo.opts.maxParentsCount
+	}
+
+	return iotago.BlockMaxParents
+}
+
+// milestoneParents assembles the parents of the next milestone out of the given tips.
+// The previous milestone is always referenced, so one parent slot is reserved for it.
+// Returns ErrTooManyParents if the tips don't fit into the remaining slots.
+func (coo *Coordinator) milestoneParents(tips iotago.BlockIDs) (iotago.BlockIDs, error) {
+	parents := append(iotago.BlockIDs{coo.state.LatestMilestoneBlockID}, tips...).RemoveDupsAndSort()
+
+	if maxParentsCount := coo.maxParentsCount(); len(parents) > maxParentsCount {
+		return nil, fmt.Errorf("%w: %d tips and the previous milestone exceed the maximum of %d parents", ErrTooManyParents, len(parents)-1, maxParentsCount)
+	}
+
+	return parents, nil
+}
+
+// checkParentsSolid checks that all parents are solid in the node.
+// Returns a soft error listing the parents that are not solid,
+// or a critical error if no BlockSolidFunc was configured.
+func (coo *Coordinator) checkParentsSolid(parents iotago.BlockIDs) error {
+	if coo.opts.blockSolidFunc == nil {
+		return common.CriticalError(ErrBlockSolidFuncMissing)
+	}
+
+	var notSolid []string
+	for _, parent := range sortedUniqueBlockIDs(parents) {
+		solid, err := coo.opts.blockSolidFunc(parent)
+		if err != nil {
+			return common.SoftError(fmt.Errorf("unable to check whether parent %s is solid: %w", parent.ToHex(), err))
+		}
+
+		if !solid {
+			notSolid = append(notSolid, parent.ToHex())
+		}
+	}
+
+	if len(notSolid) > 0 {
+		return common.SoftError(fmt.Errorf("%w: %s", ErrParentsNotSolid, strings.Join(notSolid, ", ")))
+	}
+
+	return nil
+}
+
+// Pause stops the issuance of milestones and checkpoints until Resume is called.
+// A milestone or checkpoint that is currently issued is completed before Pause returns.
+func (coo *Coordinator) Pause() {
+	// wait for the in-flight issuance
+	coo.milestoneLock.Lock()
+	coo.paused.Store(true)
+	coo.milestoneLock.Unlock()
+
+	if coo.shutdown.Load() {
+		return
+	}
+
+	coo.LogInfo("coordinator paused")
+	coo.setLifecycleState(LifecycleStatePaused)
+}
+
+// Resume continues the issuance of milestones and checkpoints after Pause was called.
+func (coo *Coordinator) Resume() {
+	if !coo.paused.Swap(false) || coo.shutdown.Load() {
+		return
+	}
+
+	coo.LogInfo("coordinator resumed")
+	coo.setLifecycleState(LifecycleStateRunning)
+}
+
+// IsPaused returns whether the issuance of milestones and checkpoints is paused.
+func (coo *Coordinator) IsPaused() bool {
+	return coo.paused.Load()
+}
+
+// Shutdown stops the issuance of milestones and checkpoints and waits until
+// a milestone or checkpoint that is currently issued is completed.
+// Pending signing retries are aborted. The event handlers stay attached,
+// the owners of the handlers are responsible for detaching them, see DetachAllEvents.
+// If the context is done before the in-flight issuance completed, the context error is returned
+// and Shutdown can be called again.
+// The coordinator must not be reused after Shutdown was called.
+func (coo *Coordinator) Shutdown(ctx context.Context) error {
+	coo.shutdown.Store(true)
+	coo.setLifecycleState(LifecycleStateShuttingDown)
+
+	// abort pending signing retries, otherwise the in-flight issuance could block the shutdown
+	coo.shutdownCancel()
+
+	// wait for the in-flight issuance, new ones are rejected because of the shutdown flag
+	lockAcquired := make(chan struct{})
+	go func() {
+		coo.milestoneLock.Lock()
+		defer coo.milestoneLock.Unlock()
+
+		close(lockAcquired)
+	}()
+
+	select {
+	case <-lockAcquired:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to wait for the in-flight milestone issuance: %w", ctx.Err())
+	}
+
+	coo.LogInfo("coordinator shut down")
+
+	return nil
+}
+
+// DetachAllEvents detaches all handlers from the events of the coordinator.
+// It is not called by Shutdown, because it also detaches the handlers of other owners.
+func (coo *Coordinator) DetachAllEvents() {
+	coo.Events.IssuedCheckpointBlock.DetachAll()
+	coo.Events.IssuedCheckpointBlockDetails.DetachAll()
+	coo.Events.IssuedMilestone.DetachAll()
+	coo.Events.IssuingMilestone.DetachAll()
+	coo.Events.MilestoneSendFailed.DetachAll()
+	coo.Events.StatePersistFailed.DetachAll()
+	coo.Events.SoftError.DetachAll()
+	coo.Events.QuorumFinished.DetachAll()
+	coo.Events.LifecycleStateChanged.DetachAll()
 }
 
 // Interval returns the interval milestones should be issued.
+// If the interval is adaptive, it is computed from the recent back pressure.
+// If a jitter is configured, it is applied again for every call.
 func (coo *Coordinator) Interval() time.Duration {
-	return coo.opts.milestoneInterval
+	return coo.jitterInterval(coo.baseInterval())
+}
+
+// baseInterval returns the interval milestones should be issued without the jitter.
+func (coo *Coordinator) baseInterval() time.Duration {
+	coo.milestoneIntervalLock.RLock()
+	defer coo.milestoneIntervalLock.RUnlock()
+
+	return coo.milestoneInterval
+}
+
+// SetInterval changes the interval milestones should be issued at runtime.
+// The new interval is used for the milestone after the next one.
+// The interval can't be changed if it is adaptive.
+func (coo *Coordinator) SetInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("milestone interval must be positive, got %v", interval)
+	}
+
+	coo.milestoneIntervalLock.Lock()
+	if coo.backPressureHistory != nil {
+		coo.milestoneIntervalLock.Unlock()
+
+		return errors.New("milestone interval is adaptive and can't be set")
+	}
+	previousInterval := coo.milestoneInterval
+	coo.milestoneInterval = interval
+	coo.milestoneIntervalLock.Unlock()
+
+	if previousInterval != interval {
+		coo.LogInfof("milestone interval changed from %v to %v", previousInterval, interval)
+	}
+
+	return nil
+}
+
+// ShouldForceMilestone returns true if more checkpoints than configured via
+// WithForceMilestoneAfterCheckpoints were issued since the last milestone.
+// This signals the caller that a milestone must be issued to bound the checkpoint-to-milestone ratio.
+func (coo *Coordinator) ShouldForceMilestone() bool {
+	if coo.opts.forceMilestoneAfterCheckpoints <= 0 {
+		return false
+	}
+
+	return int(coo.checkpointsSinceMilestone.Load()) > coo.opts.forceMilestoneAfterCheckpoints
 }
 
 // State returns the current state of the coordinator.
@@ -531,28 +2124,261 @@ func (coo *Coordinator) State() *State {
 	return coo.state
 }
 
-// AddBackPressureFunc adds a BackPressureFunc.
-// This function can be called multiple times to add additional BackPressureFunc.
-func (coo *Coordinator) AddBackPressureFunc(bpFunc BackPressureFunc) {
-	coo.backpressureFuncs = append(coo.backpressureFuncs, bpFunc)
+// ActiveSigningKeys returns the public keys used to sign the next milestone, e.g. to audit a rotation of the signing keys.
+// It returns nil if the state was not initialized yet.
+func (coo *Coordinator) ActiveSigningKeys() []iotago.MilestonePublicKey {
+	state := coo.publishedState.Load()
+	if state == nil {
+		return nil
+	}
+
+	return coo.signerProvider.MilestoneIndexSigner(state.LatestMilestoneIndex + 1).PublicKeys()
+}
+
+// LatestMilestoneIndex returns the index of the latest issued milestone.
+// It returns 0 if the state was not initialized yet.
+func (coo *Coordinator) LatestMilestoneIndex() iotago.MilestoneIndex {
+	state := coo.publishedState.Load()
+	if state == nil {
+		return 0
+	}
+
+	return state.LatestMilestoneIndex
 }
 
-// checkBackPressureFunctions checks whether any back pressure function is signaling congestion.
-func (coo *Coordinator) checkBackPressureFunctions() bool {
-	for _, f := range coo.backpressureFuncs {
-		if f() {
-			return true
-		}
+// LatestMilestoneID returns the ID of the latest issued milestone.
+// It returns an empty milestone ID if the state was not initialized yet.
+func (coo *Coordinator) LatestMilestoneID() iotago.MilestoneID {
+	state := coo.publishedState.Load()
+	if state == nil {
+		return emptyMilestoneID
 	}
 
-	return false
+	return state.LatestMilestoneID
+}
+
+// LatestMilestoneBlockID returns the block ID of the latest issued milestone.
+// It returns an empty block ID if the state was not initialized yet.
+func (coo *Coordinator) LatestMilestoneBlockID() iotago.BlockID {
+	state := coo.publishedState.Load()
+	if state == nil {
+		return iotago.EmptyBlockID()
+	}
+
+	return state.LatestMilestoneBlockID
+}
+
+// LatestMilestoneTime returns the timestamp of the latest issued milestone.
+// It returns the zero time if the state was not initialized yet.
+func (coo *Coordinator) LatestMilestoneTime() time.Time {
+	state := coo.publishedState.Load()
+	if state == nil {
+		return time.Time{}
+	}
+
+	return state.LatestMilestoneTime
 }
 
 // QuorumStats returns statistics about the response time and errors of every node in the quorum.
 func (coo *Coordinator) QuorumStats() []QuorumClientStatistic {
-	if coo.opts.quorum == nil {
+	q := coo.currentQuorum()
+	if q == nil {
 		return nil
 	}
 
-	return coo.opts.quorum.quorumStatsSnapshot()
+	return q.quorumStatsSnapshot()
+}
+
+// QuorumTopology returns the members of every quorum group, mapped by the group name.
+// Unlike QuorumStats, it only contains the static configuration, which is known before the first quorum check.
+// Returns nil if the quorum is disabled.
+func (coo *Coordinator) QuorumTopology() map[string][]QuorumGroupMember {
+	q := coo.currentQuorum()
+	if q == nil {
+		return nil
+	}
+
+	return q.topology()
+}
+
+// LastQuorumResult returns the duration, outcome and time of the last quorum check.
+// Returns nil if no quorum check finished yet.
+func (coo *Coordinator) LastQuorumResult() *QuorumFinishedResult {
+	coo.lastQuorumResultLock.RLock()
+	defer coo.lastQuorumResultLock.RUnlock()
+
+	if coo.lastQuorumResult == nil {
+		return nil
+	}
+
+	// return a copy, so the result can't be modified by the caller
+	result := *coo.lastQuorumResult
+
+	return &result
+}
+
+// setLastQuorumResult stores the result of the last quorum check.
+func (coo *Coordinator) setLastQuorumResult(result *QuorumFinishedResult) {
+	coo.lastQuorumResultLock.Lock()
+	defer coo.lastQuorumResultLock.Unlock()
+
+	coo.lastQuorumResult = result
+}
+
+// LastIssuanceTiming returns the durations of the phases of the last milestone issuance.
+// Returns nil if no milestone issuance finished yet.
+func (coo *Coordinator) LastIssuanceTiming() *IssuanceTiming {
+	coo.lastIssuanceTimingLock.RLock()
+	defer coo.lastIssuanceTimingLock.RUnlock()
+
+	if coo.lastIssuanceTiming == nil {
+		return nil
+	}
+
+	// return a copy, so the timing can't be modified by the caller
+	timing := *coo.lastIssuanceTiming
+
+	return &timing
+}
+
+// setLastIssuanceTiming stores the durations of the phases of the last milestone issuance.
+func (coo *Coordinator) setLastIssuanceTiming(timing *IssuanceTiming) {
+	coo.lastIssuanceTimingLock.Lock()
+	defer coo.lastIssuanceTimingLock.Unlock()
+
+	coo.lastIssuanceTiming = timing
+}
+
+// IssuedSinceStart returns the amount of milestones issued since the coordinator was created, including the bootstrap milestone.
+// Together with LastIssuanceError it distinguishes a freshly started coordinator from a stalled one.
+func (coo *Coordinator) IssuedSinceStart() uint64 {
+	return coo.issuedSinceStart.Load()
+}
+
+// LastIssuanceError returns the last soft or critical error of the milestone issuance.
+// The error is kept after milestones were issued successfully again, nil if no error occurred since the start.
+func (coo *Coordinator) LastIssuanceError() error {
+	coo.lastIssuanceErrLock.RLock()
+	defer coo.lastIssuanceErrLock.RUnlock()
+
+	return coo.lastIssuanceErr
+}
+
+// setLastIssuanceError stores the last error of the milestone issuance.
+func (coo *Coordinator) setLastIssuanceError(err error) {
+	coo.lastIssuanceErrLock.Lock()
+	defer coo.lastIssuanceErrLock.Unlock()
+
+	coo.lastIssuanceErr = err
+}
+
+// currentQuorum returns the quorum that is currently in use.
+func (coo *Coordinator) currentQuorum() *quorum {
+	coo.quorumLock.RLock()
+	defer coo.quorumLock.RUnlock()
+
+	return coo.quorum
+}
+
+// UpdateQuorum replaces the quorum with a new one built from the given groups.
+// The statistics of clients whose baseURL is unchanged are preserved.
+// An in-flight quorum check is not disrupted, the next milestone uses the new quorum.
+func (coo *Coordinator) UpdateQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration) error {
+	q, err := newQuorum(quorumGroups, timeout, coo.opts.quorumTransport)
+	if err != nil {
+		return err
+	}
+	q.applyOptions(coo.opts)
+
+	if err := q.validateWeightThreshold(); err != nil {
+		return err
+	}
+
+	coo.quorumLock.Lock()
+	defer coo.quorumLock.Unlock()
+
+	if coo.quorum != nil {
+		q.adoptStats(coo.quorum.quorumStatsSnapshot())
+	}
+	coo.quorum = q
+
+	coo.LogInfof("coordinator quorum updated, groups: %d", len(quorumGroups))
+
+	return nil
+}
+
+// SetMigratorEnabled defines whether receipts of the migrator service are included in milestones.
+// Disabling the migrator keeps its persisted state, so the pending migrations are included after it was enabled again.
+// The change is applied after a milestone that is currently issued was completed.
+// It has no effect if the coordinator was created without a migrator service.
+func (coo *Coordinator) SetMigratorEnabled(enabled bool) {
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	if coo.migratorService == nil || coo.migratorEnabled == enabled {
+		return
+	}
+	coo.migratorEnabled = enabled
+
+	if enabled {
+		coo.LogInfo("migrator enabled, receipts are included in milestones")
+
+		return
+	}
+	coo.LogInfo("migrator disabled, receipts are not included in milestones")
+}
+
+// MigratorEnabled returns whether receipts of the migrator service are included in milestones.
+func (coo *Coordinator) MigratorEnabled() bool {
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	return coo.migratorEnabled
+}
+
+// Readiness returns whether the coordinator is currently able to issue a milestone.
+// If the coordinator is not ready, the reasons are returned.
+func (coo *Coordinator) Readiness() (bool, []string) {
+	var reasons []string
+
+	// the published state is used, so the probe neither waits for an in-flight issuance nor delays it
+	state := coo.publishedState.Load()
+	if state == nil {
+		reasons = append(reasons, "coordinator state not initialized")
+	}
+
+	if coo.shutdown.Load() {
+		reasons = append(reasons, "coordinator shut down")
+	}
+
+	if coo.IsPaused() {
+		reasons = append(reasons, "coordinator paused")
+	}
+
+	if coo.LifecycleState() == LifecycleStateReadOnly {
+		reasons = append(reasons, "coordinator read-only")
+	}
+
+	if !coo.NodeSynced() {
+		reasons = append(reasons, "node not synced")
+	}
+
+	if state != nil && coo.opts.minMilestoneInterval > 0 {
+		if sinceLatestMilestone := coo.opts.clock.Now().Sub(state.LatestMilestoneTime); sinceLatestMilestone < coo.opts.minMilestoneInterval {
+			reasons = append(reasons, fmt.Sprintf("milestone too early, %v after the previous milestone, minimum interval: %v", sinceLatestMilestone, coo.opts.minMilestoneInterval))
+		}
+	}
+
+	if name := coo.checkBackPressureFunctions(false); name != "" {
+		reasons = append(reasons, fmt.Sprintf("node load too high, back pressure signaled by %s", name))
+	}
+
+	if state != nil {
+		nextMilestoneIndex := state.LatestMilestoneIndex + 1
+		if keysCount := len(coo.signerProvider.MilestoneIndexSigner(nextMilestoneIndex).PublicKeys()); keysCount < coo.signerProvider.PublicKeysCount() {
+			reasons = append(reasons, fmt.Sprintf("not enough valid public keys for milestone %d, got: %d, needed: %d", nextMilestoneIndex, keysCount, coo.signerProvider.PublicKeysCount()))
+		}
+	}
+
+	return len(reasons) == 0, reasons
 }
@@ -0,0 +1,3168 @@
+package coordinator_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/nodeclient"
+)
+
+var testProtoParams = &iotago.ProtocolParameters{
+	Version:       2,
+	NetworkName:   "testnet",
+	Bech32HRP:     iotago.PrefixTestnet,
+	MinPoWScore:   0,
+	BelowMaxDepth: 15,
+	RentStructure: iotago.RentStructure{
+		VByteCost:    500,
+		VBFactorData: 1,
+		VBFactorKey:  10,
+	},
+	TokenSupply: 2_779_530_283_277_761,
+}
+
+// testBlockSender records all blocks sent by the coordinator.
+type testBlockSender struct {
+	sync.Mutex
+	blocks []*iotago.Block
+}
+
+func (s *testBlockSender) sendBlock(block *iotago.Block, _ ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.blocks = append(s.blocks, block)
+
+	return block.ID()
+}
+
+func (s *testBlockSender) sentBlocks() []*iotago.Block {
+	s.Lock()
+	defer s.Unlock()
+
+	return append([]*iotago.Block{}, s.blocks...)
+}
+
+// testClock is a Clock that only changes its time if it is set.
+type testClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) set(now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.now = now
+}
+
+func randBlockIDs(t testing.TB, count int) iotago.BlockIDs {
+	t.Helper()
+
+	blockIDs := make(iotago.BlockIDs, count)
+	for i := range blockIDs {
+		_, err := rand.Read(blockIDs[i][:])
+		require.NoError(t, err)
+	}
+
+	return blockIDs
+}
+
+func testMerkleRoots(_ context.Context, _ iotago.MilestoneIndex, _ uint32, _ iotago.BlockIDs, _ iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+	return &coordinator.MilestoneMerkleRoots{}, nil
+}
+
+func testSignerProvider(t testing.TB) coordinator.MilestoneSignerProvider {
+	t.Helper()
+
+	_, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	return coordinator.NewInMemorySignerProvider(privKey)
+}
+
+// newTestCoordinator creates a coordinator with a state file in a temporary directory.
+// If no sendBlockFunc is given, the blocks are sent to the returned testBlockSender.
+func newTestCoordinator(t testing.TB, sendBlockFunc coordinator.SendBlockFunc, opts ...coordinator.Option) (*coordinator.Coordinator, *testBlockSender) {
+	t.Helper()
+
+	sender := &testBlockSender{}
+	if sendBlockFunc == nil {
+		sendBlockFunc = sender.sendBlock
+	}
+
+	opts = append([]coordinator.Option{coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state"))}, opts...)
+
+	coo, err := coordinator.New(
+		testMerkleRoots,
+		func() bool { return true },
+		func() *iotago.ProtocolParameters { return testProtoParams },
+		testSignerProvider(t),
+		nil,
+		nil,
+		sendBlockFunc,
+		opts...,
+	)
+	require.NoError(t, err)
+
+	return coo, sender
+}
+
+// newBootstrappedTestCoordinator creates a test coordinator and bootstraps the network.
+func newBootstrappedTestCoordinator(t *testing.T, sendBlockFunc coordinator.SendBlockFunc, opts ...coordinator.Option) (*coordinator.Coordinator, *testBlockSender) {
+	t.Helper()
+
+	coo, sender := newTestCoordinator(t, sendBlockFunc, opts...)
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+
+	return coo, sender
+}
+
+func TestInitStateConcurrent(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil)
+
+	const callsCount = 10
+
+	wg := &sync.WaitGroup{}
+	errs := make(chan error, callsCount)
+	for i := 0; i < callsCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+
+			continue
+		}
+		require.ErrorIs(t, err, coordinator.ErrStateAlreadyInitialized)
+	}
+	require.Equal(t, 1, succeeded)
+
+	require.EqualValues(t, 0, coo.State().LatestMilestoneIndex)
+}
+
+func TestInitStateTwice(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+
+	err := coo.InitState(false, 0, &coordinator.LatestMilestoneInfo{Index: 1})
+	require.ErrorIs(t, err, coordinator.ErrStateAlreadyInitialized)
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+}
+
+func TestIssueMilestoneReservesParentForPreviousMilestone(t *testing.T) {
+	coo, sender := newBootstrappedTestCoordinator(t, nil)
+	previousMilestoneBlockID := coo.State().LatestMilestoneBlockID
+
+	// exactly the maximum amount of tips leaves no room for the previous milestone
+	_, err := coo.IssueMilestone(randBlockIDs(t, iotago.BlockMaxParents))
+	require.ErrorIs(t, err, coordinator.ErrTooManyParents)
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+
+	// the previous milestone is only counted once
+	tips := append(randBlockIDs(t, iotago.BlockMaxParents-1), previousMilestoneBlockID)
+	_, err = coo.IssueMilestone(tips)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+
+	sentBlocks := sender.sentBlocks()
+	milestoneBlock := sentBlocks[len(sentBlocks)-1]
+	require.Len(t, milestoneBlock.Parents, iotago.BlockMaxParents)
+	require.Contains(t, milestoneBlock.Parents, previousMilestoneBlockID)
+}
+
+func TestIssueMilestoneTooManyParents(t *testing.T) {
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithMaxParentsCount(4))
+
+	_, err := coo.IssueMilestone(randBlockIDs(t, 4))
+	require.ErrorIs(t, err, coordinator.ErrTooManyParents)
+	require.Error(t, common.IsSoftError(err))
+	require.Len(t, sender.sentBlocks(), 1)
+
+	// duplicates are not counted
+	tips := randBlockIDs(t, 3)
+	_, err = coo.IssueMilestone(append(tips, tips...))
+	require.NoError(t, err)
+	require.Len(t, sender.sentBlocks(), 2)
+}
+
+func TestLifecycleStateTransitions(t *testing.T) {
+	var changes []*coordinator.LifecycleStateChange
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithLifecycleStateChangedFunc(func(change *coordinator.LifecycleStateChange) {
+		changes = append(changes, change)
+	}))
+	require.Equal(t, coordinator.LifecycleStateRunning, coo.LifecycleState())
+
+	// a soft error degrades the coordinator
+	_, err := coo.IssueMilestone(randBlockIDs(t, iotago.BlockMaxParents))
+	require.Error(t, err)
+	require.Equal(t, coordinator.LifecycleStateDegraded, coo.LifecycleState())
+
+	// a successful milestone recovers the coordinator
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Equal(t, coordinator.LifecycleStateRunning, coo.LifecycleState())
+
+	expectedTransitions := [][2]coordinator.LifecycleState{
+		{coordinator.LifecycleStateInitializing, coordinator.LifecycleStateBootstrapping},
+		{coordinator.LifecycleStateBootstrapping, coordinator.LifecycleStateRunning},
+		{coordinator.LifecycleStateRunning, coordinator.LifecycleStateDegraded},
+		{coordinator.LifecycleStateDegraded, coordinator.LifecycleStateRunning},
+	}
+	require.Len(t, changes, len(expectedTransitions))
+	for i, transition := range expectedTransitions {
+		require.Equal(t, transition[0], changes[i].Previous)
+		require.Equal(t, transition[1], changes[i].Current)
+	}
+}
+
+func TestLifecycleStateChangedHandlerCallsGetters(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil)
+
+	// the event is triggered after the milestone lock was released, so handlers can use the getters
+	var readiness []bool
+	var latestMilestoneIndexes []iotago.MilestoneIndex
+	coo.Events.LifecycleStateChanged.Hook(events.NewClosure(func(change *coordinator.LifecycleStateChange) {
+		ready, _ := coo.Readiness()
+		readiness = append(readiness, ready)
+		latestMilestoneIndexes = append(latestMilestoneIndexes, coo.LatestMilestoneIndex())
+
+		_, err := coo.Health()
+		require.NoError(t, err)
+	}))
+
+	bootstrapDone := make(chan error, 1)
+	go func() {
+		if err := coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}); err != nil {
+			bootstrapDone <- err
+
+			return
+		}
+
+		_, err := coo.Bootstrap()
+		bootstrapDone <- err
+	}()
+
+	select {
+	case err := <-bootstrapDone:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "lifecycle state changed handler deadlocked")
+	}
+	require.Equal(t, []bool{true, true}, readiness)
+	require.Equal(t, []iotago.MilestoneIndex{0, 1}, latestMilestoneIndexes)
+}
+
+func TestLifecycleStateReadOnly(t *testing.T) {
+	nodeMilestoneIndex := iotago.MilestoneIndex(1)
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithLatestMilestoneIndexFunc(func() iotago.MilestoneIndex {
+		return nodeMilestoneIndex
+	}))
+
+	// the node knows milestones the coordinator didn't issue
+	nodeMilestoneIndex = 2
+	_, err := coo.IssueMilestone(nil)
+	require.True(t, coordinator.IsCritical(err))
+	require.Equal(t, coordinator.LifecycleStateReadOnly, coo.LifecycleState())
+
+	// the issuance is rejected even if the cause of the critical error is resolved
+	nodeMilestoneIndex = 1
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorReadOnly)
+	require.True(t, coordinator.IsSoft(err))
+
+	_, err = coo.IssueCheckpoint(0, randBlockIDs(t, 1)[0], randBlockIDs(t, 3))
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorReadOnly)
+	require.True(t, coordinator.IsSoft(err))
+	require.Len(t, sender.sentBlocks(), 1)
+
+	// pausing and resuming doesn't leave the read-only state
+	coo.Pause()
+	coo.Resume()
+	require.Equal(t, coordinator.LifecycleStateReadOnly, coo.LifecycleState())
+
+	coo.ClearReadOnly()
+	require.Equal(t, coordinator.LifecycleStateRunning, coo.LifecycleState())
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+
+	// clearing the read-only state has no effect on a coordinator that is not read-only
+	coo.Pause()
+	coo.ClearReadOnly()
+	require.Equal(t, coordinator.LifecycleStatePaused, coo.LifecycleState())
+}
+
+func TestReadOnlyProbes(t *testing.T) {
+	nodeMilestoneIndex := iotago.MilestoneIndex(1)
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithLatestMilestoneIndexFunc(func() iotago.MilestoneIndex {
+		return nodeMilestoneIndex
+	}))
+
+	// the node knows milestones the coordinator didn't issue
+	nodeMilestoneIndex = 2
+	_, err := coo.IssueMilestone(nil)
+	require.True(t, coordinator.IsCritical(err))
+	require.Equal(t, coordinator.LifecycleStateReadOnly, coo.LifecycleState())
+
+	// the probes report the read-only coordinator even if the cause of the critical error is resolved
+	nodeMilestoneIndex = 1
+
+	ready, reasons := coo.Readiness()
+	require.False(t, ready)
+	require.Equal(t, []string{"coordinator read-only"}, reasons)
+
+	health, err := coo.Health()
+	require.NoError(t, err)
+	require.False(t, health.Healthy)
+	require.True(t, health.ReadOnly)
+
+	coo.ClearReadOnly()
+
+	ready, reasons = coo.Readiness()
+	require.True(t, ready, reasons)
+
+	health, err = coo.Health()
+	require.NoError(t, err)
+	require.True(t, health.Healthy)
+	require.False(t, health.ReadOnly)
+}
+
+func TestIssueCheckpointTipsPerBlock(t *testing.T) {
+	tests := []struct {
+		name                string
+		maxParentsCount     int
+		tipsCount           int
+		expectedBlocksCount int
+	}{
+		{name: "two parents", maxParentsCount: 2, tipsCount: 5, expectedBlocksCount: 5},
+		{name: "protocol default", maxParentsCount: iotago.BlockMaxParents, tipsCount: 20, expectedBlocksCount: 3},
+		{name: "large value", maxParentsCount: 1000, tipsCount: 5, expectedBlocksCount: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			coo, sender := newTestCoordinator(t, nil, coordinator.WithMaxParentsCount(test.maxParentsCount))
+
+			lastCheckpointBlockID := randBlockIDs(t, 1)[0]
+			tips := randBlockIDs(t, test.tipsCount)
+
+			checkpointBlockID, err := coo.IssueCheckpoint(0, lastCheckpointBlockID, tips)
+			require.NoError(t, err)
+
+			sentBlocks := sender.sentBlocks()
+			require.Len(t, sentBlocks, test.expectedBlocksCount)
+
+			referencedTips := make(map[iotago.BlockID]struct{})
+			for _, block := range sentBlocks {
+				require.LessOrEqual(t, len(block.Parents), test.maxParentsCount)
+
+				// every checkpoint block is chained to the previous one
+				require.Contains(t, block.Parents, lastCheckpointBlockID)
+				for _, parent := range block.Parents {
+					if parent != lastCheckpointBlockID {
+						referencedTips[parent] = struct{}{}
+					}
+				}
+
+				lastCheckpointBlockID, err = block.ID()
+				require.NoError(t, err)
+			}
+			require.Len(t, referencedTips, test.tipsCount)
+			require.Equal(t, lastCheckpointBlockID, checkpointBlockID)
+		})
+	}
+}
+
+func TestIssuedCheckpointBlockDetailsEvent(t *testing.T) {
+	coo, sender := newTestCoordinator(t, nil, coordinator.WithMaxParentsCount(4))
+
+	var infos []*coordinator.IssuedCheckpointBlockInfo
+	coo.Events.IssuedCheckpointBlockDetails.Hook(events.NewClosure(func(info *coordinator.IssuedCheckpointBlockInfo) {
+		infos = append(infos, info)
+	}))
+
+	tips := randBlockIDs(t, 7)
+	checkpointBlockID, err := coo.IssueCheckpoint(3, randBlockIDs(t, 1)[0], tips)
+	require.NoError(t, err)
+
+	sentBlocks := sender.sentBlocks()
+	require.Len(t, infos, len(sentBlocks))
+
+	referencedTips := make(map[iotago.BlockID]struct{})
+	for i, info := range infos {
+		blockID, err := sentBlocks[i].ID()
+		require.NoError(t, err)
+
+		require.Equal(t, 3, info.CheckpointIndex)
+		require.Equal(t, i, info.BlockIndex)
+		require.Equal(t, len(sentBlocks), info.BlocksTotal)
+		require.Equal(t, blockID, info.BlockID)
+		require.Equal(t, sentBlocks[i].Parents, info.Parents)
+
+		for _, parent := range info.Parents {
+			referencedTips[parent] = struct{}{}
+		}
+	}
+	require.Equal(t, checkpointBlockID, infos[len(infos)-1].BlockID)
+
+	// every tip can be mapped to the checkpoint block that referenced it
+	for _, tip := range tips {
+		require.Contains(t, referencedTips, tip)
+	}
+}
+
+func TestIssueCheckpointFanOut(t *testing.T) {
+	coo, sender := newTestCoordinator(t, nil, coordinator.WithMaxParentsCount(3), coordinator.WithCheckpointStrategy(coordinator.CheckpointStrategyFanOut))
+
+	lastCheckpointBlockID := randBlockIDs(t, 1)[0]
+	tips := randBlockIDs(t, 6)
+
+	checkpointBlockID, err := coo.IssueCheckpoint(0, lastCheckpointBlockID, tips)
+	require.NoError(t, err)
+
+	sentBlocks := sender.sentBlocks()
+	require.Len(t, sentBlocks, 3)
+
+	// every checkpoint block references the last checkpoint instead of the previous block
+	referencedTips := make(map[iotago.BlockID]struct{})
+	for _, block := range sentBlocks {
+		require.Len(t, block.Parents, 3)
+		require.Contains(t, block.Parents, lastCheckpointBlockID)
+		for _, parent := range block.Parents {
+			referencedTips[parent] = struct{}{}
+		}
+	}
+	for _, tip := range tips {
+		require.Contains(t, referencedTips, tip)
+	}
+
+	// the last sent block is returned
+	lastSentBlockID, err := sentBlocks[len(sentBlocks)-1].ID()
+	require.NoError(t, err)
+	require.Equal(t, lastSentBlockID, checkpointBlockID)
+}
+
+func TestParseCheckpointStrategy(t *testing.T) {
+	for _, strategy := range []coordinator.CheckpointStrategy{coordinator.CheckpointStrategyChained, coordinator.CheckpointStrategyFanOut} {
+		parsed, err := coordinator.ParseCheckpointStrategy(strategy.String())
+		require.NoError(t, err)
+		require.Equal(t, strategy, parsed)
+	}
+
+	_, err := coordinator.ParseCheckpointStrategy("tree")
+	require.Error(t, err)
+}
+
+func TestIssueCheckpointNoRoomForTips(t *testing.T) {
+	coo, sender := newTestCoordinator(t, nil, coordinator.WithMaxParentsCount(1))
+
+	_, err := coo.IssueCheckpoint(0, randBlockIDs(t, 1)[0], randBlockIDs(t, 3))
+	require.ErrorIs(t, err, coordinator.ErrTooManyParents)
+	require.Empty(t, sender.sentBlocks())
+}
+
+func TestIssueCheckpointWithCancelledContext(t *testing.T) {
+	coo, sender := newTestCoordinator(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	lastCheckpointBlockID := randBlockIDs(t, 1)[0]
+	checkpointBlockID, err := coo.IssueCheckpointWithContext(ctx, 0, lastCheckpointBlockID, randBlockIDs(t, 3))
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, lastCheckpointBlockID, checkpointBlockID)
+	require.Empty(t, sender.sentBlocks())
+}
+
+func TestIssueCheckpointCancelledMidBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sender := &testBlockSender{}
+	coo, _ := newTestCoordinator(t, func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		blockID, err := sender.sendBlock(block, msIndex...)
+		if len(sender.sentBlocks()) == 2 {
+			// cancel the batch after the second block was sent
+			cancel()
+		}
+
+		return blockID, err
+	}, coordinator.WithMaxParentsCount(2))
+
+	tips := randBlockIDs(t, 5)
+	checkpointBlockID, err := coo.IssueCheckpointWithContext(ctx, 0, randBlockIDs(t, 1)[0], tips)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// the ID of the last sent block is returned
+	sentBlocks := sender.sentBlocks()
+	require.Len(t, sentBlocks, 2)
+	lastSentBlockID, err := sentBlocks[1].ID()
+	require.NoError(t, err)
+	require.Equal(t, lastSentBlockID, checkpointBlockID)
+
+	// the caller can resume from the returned block ID with the remaining tips
+	referencedTips := make(map[iotago.BlockID]struct{})
+	for _, block := range sentBlocks {
+		for _, parent := range block.Parents {
+			referencedTips[parent] = struct{}{}
+		}
+	}
+	var remainingTips iotago.BlockIDs
+	for _, tip := range tips {
+		if _, referenced := referencedTips[tip]; !referenced {
+			remainingTips = append(remainingTips, tip)
+		}
+	}
+	require.Len(t, remainingTips, 3)
+
+	_, err = coo.IssueCheckpoint(0, checkpointBlockID, remainingTips)
+	require.NoError(t, err)
+
+	sentBlocks = sender.sentBlocks()
+	require.Len(t, sentBlocks, 5)
+	require.Contains(t, sentBlocks[2].Parents, checkpointBlockID)
+}
+
+func TestComputeNextMilestone(t *testing.T) {
+	coo, sender := newBootstrappedTestCoordinator(t, nil)
+	stateBefore := *coo.State()
+	sentBlocksBefore := len(sender.sentBlocks())
+
+	tips := randBlockIDs(t, 3)
+	milestoneBlock, merkleRoots, err := coo.ComputeNextMilestone(tips)
+	require.NoError(t, err)
+	require.NotNil(t, merkleRoots)
+
+	milestonePayload, ok := milestoneBlock.Payload.(*iotago.Milestone)
+	require.True(t, ok)
+	require.Equal(t, stateBefore.LatestMilestoneIndex+1, milestonePayload.Index)
+	require.Equal(t, stateBefore.LatestMilestoneID, milestonePayload.PreviousMilestoneID)
+	require.Contains(t, milestoneBlock.Parents, stateBefore.LatestMilestoneBlockID)
+
+	// nothing was sent and the state is unchanged
+	require.Len(t, sender.sentBlocks(), sentBlocksBefore)
+	require.Equal(t, stateBefore, *coo.State())
+}
+
+func TestIssueMilestoneTimestampsStrictlyIncreasing(t *testing.T) {
+	clock := &testClock{now: time.Unix(1_000_000, 0)}
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithClock(clock))
+
+	// issue several milestones within the same second
+	for i := 0; i < 3; i++ {
+		_, err := coo.IssueMilestone(nil)
+		require.NoError(t, err)
+	}
+
+	// the clock goes backwards
+	clock.set(time.Unix(999_000, 0))
+	_, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	// the clock is ahead of the previous milestone again
+	clock.set(time.Unix(1_000_010, 0))
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	var timestamps []uint32
+	for _, block := range sender.sentBlocks() {
+		milestonePayload, ok := block.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		timestamps = append(timestamps, milestonePayload.Timestamp)
+	}
+	require.Equal(t, []uint32{1_000_000, 1_000_001, 1_000_002, 1_000_003, 1_000_004, 1_000_010}, timestamps)
+	require.EqualValues(t, 1_000_010, coo.State().LatestMilestoneTime.Unix())
+}
+
+func TestIssueMilestoneSendRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         int
+		expectedAttempts int
+		expectCritical   bool
+	}{
+		{name: "transient failure", failures: 2, expectedAttempts: 3, expectCritical: false},
+		{name: "retries exhausted", failures: 3, expectedAttempts: 3, expectCritical: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := &testBlockSender{}
+			failSending := false
+			attempts := 0
+			sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+				if failSending {
+					attempts++
+					if attempts <= test.failures {
+						return iotago.EmptyBlockID(), errors.New("node not reachable")
+					}
+				}
+
+				return sender.sendBlock(block, msIndex...)
+			}
+
+			coo, _ := newBootstrappedTestCoordinator(t, sendBlock, coordinator.WithSendBlockRetry(3, time.Millisecond))
+
+			var sendFailedIndices []iotago.MilestoneIndex
+			coo.Events.MilestoneSendFailed.Hook(events.NewClosure(func(index iotago.MilestoneIndex, err error) {
+				require.Error(t, err)
+				sendFailedIndices = append(sendFailedIndices, index)
+			}))
+
+			failSending = true
+			_, err := coo.IssueMilestone(nil)
+			require.Equal(t, test.expectedAttempts, attempts)
+			if test.expectCritical {
+				require.Error(t, err)
+				require.Error(t, common.IsCriticalError(err))
+				require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+				require.Equal(t, []iotago.MilestoneIndex{2}, sendFailedIndices)
+
+				return
+			}
+			require.Empty(t, sendFailedIndices)
+			require.NoError(t, err)
+			require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+			require.Len(t, sender.sentBlocks(), 2)
+		})
+	}
+}
+
+func TestIssueMilestoneSendFailureRestoresStateFile(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+
+	sender := &testBlockSender{}
+	failSending := false
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if failSending {
+			return iotago.EmptyBlockID(), fmt.Errorf("%w: node rejected the block", coordinator.ErrBlockNotSent)
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	}
+
+	coo, _ := newBootstrappedTestCoordinator(t, sendBlock, coordinator.WithStateFilePath(stateFilePath))
+
+	stateFileBefore, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+
+	failSending = true
+	_, err = coo.IssueMilestone(nil)
+	require.Error(t, common.IsCriticalError(err))
+
+	stateFileAfter, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+	require.Equal(t, stateFileBefore, stateFileAfter)
+
+	_, err = os.Stat(stateFilePath + "_old")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestIssueMilestoneAmbiguousSendFailure(t *testing.T) {
+	errTimeout := errors.New("request timed out")
+
+	// newCoordinator creates a coordinator whose node accepts the first milestone after the bootstrap milestone,
+	// but the request times out, so the coordinator doesn't know whether it was sent.
+	newCoordinator := func(t *testing.T, opts ...coordinator.Option) (*coordinator.Coordinator, *testBlockSender, string) {
+		t.Helper()
+
+		stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+		sender := &testBlockSender{}
+		var sends atomic.Int32
+		sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+			blockID, err := sender.sendBlock(block, msIndex...)
+			if sends.Add(1) == 2 {
+				return iotago.EmptyBlockID(), errTimeout
+			}
+
+			return blockID, err
+		}
+
+		coo, _ := newBootstrappedTestCoordinator(t, sendBlock, append([]coordinator.Option{coordinator.WithStateFilePath(stateFilePath)}, opts...)...)
+		_, err := coo.IssueMilestone(nil)
+		require.ErrorIs(t, err, errTimeout)
+		require.True(t, coordinator.IsCritical(err))
+
+		// the old state file is kept, so a restart with crash recovery checks whether the milestone was issued
+		_, err = os.Stat(stateFilePath)
+		require.True(t, os.IsNotExist(err))
+		require.FileExists(t, stateFilePath+"_old")
+
+		// the operator allows the issuance again after the critical error
+		require.Equal(t, coordinator.LifecycleStateReadOnly, coo.LifecycleState())
+		coo.ClearReadOnly()
+
+		return coo, sender, stateFilePath
+	}
+
+	t.Run("without crash recovery", func(t *testing.T) {
+		coo, sender, _ := newCoordinator(t)
+
+		// the index is not used again without checking whether the milestone was issued
+		_, err := coo.IssueMilestone(nil)
+		require.ErrorIs(t, err, coordinator.ErrMilestoneDeliveryUnknown)
+		require.True(t, coordinator.IsCritical(err))
+		require.Len(t, sender.sentBlocks(), 2)
+	})
+
+	t.Run("milestone was issued", func(t *testing.T) {
+		var sender *testBlockSender
+		milestoneExists := func(index iotago.MilestoneIndex) (*coordinator.LatestMilestoneInfo, error) {
+			milestones := sentMilestones(t, sender)
+			milestonePayload, exists := milestones[index]
+			if !exists {
+				//nolint:nilnil // nil signals that the milestone does not exist
+				return nil, nil
+			}
+
+			milestoneID, err := milestonePayload.ID()
+			require.NoError(t, err)
+
+			return &coordinator.LatestMilestoneInfo{Index: index, Timestamp: milestonePayload.Timestamp, MilestoneID: milestoneID}, nil
+		}
+
+		var coo *coordinator.Coordinator
+		var stateFilePath string
+		coo, sender, stateFilePath = newCoordinator(t, coordinator.WithCrashRecovery(milestoneExists))
+
+		// the sent milestone is adopted and the next milestone follows it
+		_, err := coo.IssueMilestone(nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, coo.State().LatestMilestoneIndex)
+		require.FileExists(t, stateFilePath)
+
+		milestones := sentMilestones(t, sender)
+		require.Len(t, milestones, 3)
+		adoptedMilestoneID, err := milestones[2].ID()
+		require.NoError(t, err)
+		require.Equal(t, adoptedMilestoneID, milestones[3].PreviousMilestoneID)
+	})
+
+	t.Run("milestone was not issued", func(t *testing.T) {
+		milestoneExists := func(index iotago.MilestoneIndex) (*coordinator.LatestMilestoneInfo, error) {
+			//nolint:nilnil // nil signals that the milestone does not exist
+			return nil, nil
+		}
+
+		coo, sender, stateFilePath := newCoordinator(t, coordinator.WithCrashRecovery(milestoneExists))
+
+		// the index is used again
+		_, err := coo.IssueMilestone(nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+		require.FileExists(t, stateFilePath)
+		require.Len(t, sender.sentBlocks(), 3)
+	})
+
+	t.Run("node not synced up to the latest milestone", func(t *testing.T) {
+		milestoneExists := func(index iotago.MilestoneIndex) (*coordinator.LatestMilestoneInfo, error) {
+			//nolint:nilnil // nil signals that the milestone does not exist
+			return nil, nil
+		}
+
+		nodeMilestoneIndex := iotago.MilestoneIndex(0)
+		coo, sender, stateFilePath := newCoordinator(t,
+			coordinator.WithCrashRecovery(milestoneExists),
+			coordinator.WithLatestMilestoneIndexFunc(func() iotago.MilestoneIndex {
+				return nodeMilestoneIndex
+			}),
+		)
+
+		// the node doesn't know the bootstrap milestone yet, so the sent milestone may still reach it
+		_, err := coo.IssueMilestone(nil)
+		require.ErrorIs(t, err, coordinator.ErrMilestoneDeliveryUnknown)
+		require.True(t, coordinator.IsSoft(err))
+		require.Len(t, sender.sentBlocks(), 2)
+		require.FileExists(t, stateFilePath+"_old")
+
+		// the index is used again once the node is synced up to the latest milestone
+		nodeMilestoneIndex = 1
+		_, err = coo.IssueMilestone(nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+		require.FileExists(t, stateFilePath)
+		require.Len(t, sender.sentBlocks(), 3)
+	})
+}
+
+func TestIssueMilestoneWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sender := &testBlockSender{}
+	cancelOnSend := false
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if cancelOnSend {
+			// the caller is shutting down while the block is sent
+			cancel()
+
+			return iotago.EmptyBlockID(), fmt.Errorf("%w: request cancelled", coordinator.ErrBlockNotSent)
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	}
+
+	coo, _ := newBootstrappedTestCoordinator(t, sendBlock)
+
+	cancelOnSend = true
+	_, err := coo.IssueMilestoneWithContext(ctx, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, coordinator.IsCritical(err))
+	require.True(t, coordinator.IsSoft(err))
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+
+	// the cancellation doesn't stop the coordinator, the next milestone uses the same index
+	cancelOnSend = false
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+}
+
+func TestPreSendHook(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+
+	errInvariant := errors.New("invariant violated")
+	rejectMilestone := false
+	var hookedIndices []iotago.MilestoneIndex
+	preSendHook := func(block *iotago.Block, index iotago.MilestoneIndex) error {
+		milestonePayload, ok := block.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		require.Equal(t, index, milestonePayload.Index)
+
+		if rejectMilestone {
+			return errInvariant
+		}
+		hookedIndices = append(hookedIndices, index)
+
+		return nil
+	}
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath), coordinator.WithPreSendHook(preSendHook))
+	require.Equal(t, []iotago.MilestoneIndex{1}, hookedIndices)
+
+	stateFileBefore, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+
+	rejectMilestone = true
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, errInvariant)
+	require.Error(t, common.IsCriticalError(err))
+
+	// the rejected milestone was neither sent nor stored
+	require.Len(t, sender.sentBlocks(), 1)
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+
+	stateFileAfter, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+	require.Equal(t, stateFileBefore, stateFileAfter)
+}
+
+func TestPostSendHook(t *testing.T) {
+	var hookedIndices []iotago.MilestoneIndex
+	var hookedMilestoneID iotago.MilestoneID
+	var hookedBlockID iotago.BlockID
+	postSendHook := func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, blockID iotago.BlockID) error {
+		hookedIndices = append(hookedIndices, index)
+		hookedMilestoneID = milestoneID
+		hookedBlockID = blockID
+
+		return errors.New("audit log not reachable")
+	}
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithPostSendHook(postSendHook))
+
+	// errors of the hook don't fail the issuance
+	blockID, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestoneIndex{1, 2}, hookedIndices)
+	require.Len(t, sender.sentBlocks(), 2)
+	require.Equal(t, blockID, hookedBlockID)
+	require.Equal(t, coo.State().LatestMilestoneID, hookedMilestoneID)
+}
+
+func TestInitStateCrashRecovery(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath))
+	stateFileBefore, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	issuedState := *coo.State()
+
+	// simulate a crash after the milestone was sent, but before the state file was written
+	require.NoError(t, os.Remove(stateFilePath))
+	require.NoError(t, os.WriteFile(stateFilePath+"_old", stateFileBefore, 0600))
+
+	sentBlocks := sender.sentBlocks()
+	milestonePayload, ok := sentBlocks[len(sentBlocks)-1].Payload.(*iotago.Milestone)
+	require.True(t, ok)
+
+	milestoneExists := func(index iotago.MilestoneIndex) (*coordinator.LatestMilestoneInfo, error) {
+		if index != milestonePayload.Index {
+			//nolint:nilnil // nil signals that the milestone does not exist
+			return nil, nil
+		}
+
+		return &coordinator.LatestMilestoneInfo{
+			Index:       milestonePayload.Index,
+			Timestamp:   milestonePayload.Timestamp,
+			MilestoneID: issuedState.LatestMilestoneID,
+		}, nil
+	}
+
+	// without crash recovery the state file is missing
+	cooWithoutRecovery, _ := newTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath))
+	require.Error(t, cooWithoutRecovery.InitState(false, 0, &coordinator.LatestMilestoneInfo{Index: 2}))
+
+	recoveredCoo, recoveredSender := newTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath), coordinator.WithCrashRecovery(milestoneExists))
+	require.NoError(t, recoveredCoo.InitState(false, 0, &coordinator.LatestMilestoneInfo{Index: 2}))
+	require.Equal(t, issuedState.LatestMilestoneIndex, recoveredCoo.State().LatestMilestoneIndex)
+	require.Equal(t, issuedState.LatestMilestoneID, recoveredCoo.State().LatestMilestoneID)
+	require.FileExists(t, stateFilePath)
+
+	// the next milestone continues the chain instead of issuing a duplicate
+	_, err = recoveredCoo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	recoveredMilestone, ok := recoveredSender.sentBlocks()[0].Payload.(*iotago.Milestone)
+	require.True(t, ok)
+	require.EqualValues(t, 3, recoveredMilestone.Index)
+	require.Equal(t, issuedState.LatestMilestoneID, recoveredMilestone.PreviousMilestoneID)
+}
+
+func TestPauseResume(t *testing.T) {
+	coo, sender := newBootstrappedTestCoordinator(t, nil)
+
+	coo.Pause()
+	require.True(t, coo.IsPaused())
+	require.Equal(t, coordinator.LifecycleStatePaused, coo.LifecycleState())
+
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorPaused)
+	require.Error(t, common.IsSoftError(err))
+
+	_, err = coo.IssueCheckpoint(0, coo.State().LatestMilestoneBlockID, randBlockIDs(t, 1))
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorPaused)
+
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+	require.Len(t, sender.sentBlocks(), 1)
+	require.Equal(t, coordinator.LifecycleStatePaused, coo.LifecycleState())
+
+	coo.Resume()
+	require.False(t, coo.IsPaused())
+	require.Equal(t, coordinator.LifecycleStateRunning, coo.LifecycleState())
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+}
+
+func TestPauseWaitsForInFlightMilestone(t *testing.T) {
+	sender := &testBlockSender{}
+	blockSending := false
+	sendStarted := make(chan struct{})
+	releaseSend := make(chan struct{})
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if blockSending {
+			close(sendStarted)
+			<-releaseSend
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	}
+
+	coo, _ := newBootstrappedTestCoordinator(t, sendBlock)
+	blockSending = true
+
+	issueDone := make(chan error, 1)
+	go func() {
+		_, err := coo.IssueMilestone(nil)
+		issueDone <- err
+	}()
+	<-sendStarted
+
+	pauseDone := make(chan struct{})
+	go func() {
+		coo.Pause()
+		close(pauseDone)
+	}()
+
+	select {
+	case <-pauseDone:
+		require.FailNow(t, "pause returned before the in-flight milestone was completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(releaseSend)
+	require.NoError(t, <-issueDone)
+	<-pauseDone
+
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+	require.True(t, coo.IsPaused())
+}
+
+func TestShutdown(t *testing.T) {
+	sender := &testBlockSender{}
+	blockSending := false
+	sendStarted := make(chan struct{})
+	releaseSend := make(chan struct{})
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if blockSending {
+			close(sendStarted)
+			<-releaseSend
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	}
+
+	coo, _ := newBootstrappedTestCoordinator(t, sendBlock)
+	blockSending = true
+
+	issuedCount := 0
+	coo.Events.IssuedMilestone.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, _ iotago.MilestoneID, _ iotago.BlockID) {
+		issuedCount++
+	}))
+
+	issueDone := make(chan error, 1)
+	go func() {
+		_, err := coo.IssueMilestone(nil)
+		issueDone <- err
+	}()
+	<-sendStarted
+
+	// the in-flight milestone is not completed before the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, coo.Shutdown(ctx), context.DeadlineExceeded)
+	require.Equal(t, coordinator.LifecycleStateShuttingDown, coo.LifecycleState())
+
+	close(releaseSend)
+	require.NoError(t, <-issueDone)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+
+	require.NoError(t, coo.Shutdown(context.Background()))
+
+	// new milestones are rejected after the shutdown
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorShutdown)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+
+	// the handlers are not detached by the shutdown, their owners detach them
+	require.Equal(t, 1, issuedCount)
+	coo.Events.IssuedMilestone.Trigger(iotago.MilestoneIndex(2), iotago.MilestoneID{}, iotago.EmptyBlockID())
+	require.Equal(t, 2, issuedCount)
+}
+
+func TestShutdownAbortsSigningRetries(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil,
+		coordinator.WithSignerProvider(&failingSignerProvider{testSignerProvider(t)}),
+		coordinator.WithSigningRetryAmount(10),
+		coordinator.WithSigningRetryTimeout(time.Hour),
+	)
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+
+	bootstrapDone := make(chan error, 1)
+	go func() {
+		_, err := coo.Bootstrap()
+		bootstrapDone <- err
+	}()
+
+	// the shutdown doesn't wait for the pending signing retries
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, coo.Shutdown(ctx))
+
+	err := <-bootstrapDone
+	require.ErrorIs(t, err, context.Canceled)
+	require.Error(t, common.IsCriticalError(err))
+}
+
+func TestNamedBackPressureFunc(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+
+	coo.AddNamedBackPressureFunc("database size", func() bool { return false })
+	coo.AddNamedBackPressureFunc("mempool depth", func() bool { return true })
+
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrNodeLoadTooHigh)
+	require.Error(t, common.IsSoftError(err))
+	require.Contains(t, err.Error(), "mempool depth")
+
+	ready, reasons := coo.Readiness()
+	require.False(t, ready)
+	require.Contains(t, reasons, "node load too high, back pressure signaled by mempool depth")
+}
+
+func TestSoftErrorEventHandlerCallsGetters(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+	coo.AddNamedBackPressureFunc("mempool depth", func() bool { return true })
+
+	// the event is triggered after the milestone lock was released, so handlers can use the getters
+	var latestMilestoneIndexes []iotago.MilestoneIndex
+	coo.Events.SoftError.Hook(events.NewClosure(func(err error) {
+		latestMilestoneIndexes = append(latestMilestoneIndexes, coo.LatestMilestoneIndex())
+	}))
+
+	issueDone := make(chan error, 2)
+	go func() {
+		_, err := coo.IssueMilestone(nil)
+		issueDone <- err
+
+		_, err = coo.IssueCheckpoint(0, coo.State().LatestMilestoneBlockID, randBlockIDs(t, 1))
+		issueDone <- err
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-issueDone:
+			require.ErrorIs(t, err, coordinator.ErrNodeLoadTooHigh)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "soft error event handler deadlocked")
+		}
+	}
+	require.Equal(t, []iotago.MilestoneIndex{1, 1}, latestMilestoneIndexes)
+}
+
+func TestIssuanceEventHandlersCallGetters(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+
+	// the events are triggered after the milestone lock was released, so handlers can use the getters
+	var issuingIndexes, issuedIndexes []iotago.MilestoneIndex
+	var activeSigningKeys []iotago.MilestonePublicKey
+	var checkpointMilestoneIDs []iotago.MilestoneID
+	coo.Events.IssuingMilestone.Hook(events.NewClosure(func(index iotago.MilestoneIndex, _ iotago.MilestoneID, _ iotago.BlockIDs) {
+		// the latest milestone is still the previous one while the milestone is sent
+		issuingIndexes = append(issuingIndexes, coo.LatestMilestoneIndex())
+	}))
+	coo.Events.IssuedMilestone.Hook(events.NewClosure(func(index iotago.MilestoneIndex, _ iotago.MilestoneID, _ iotago.BlockID) {
+		issuedIndexes = append(issuedIndexes, coo.LatestMilestoneIndex())
+		activeSigningKeys = coo.ActiveSigningKeys()
+	}))
+	coo.Events.IssuedCheckpointBlock.Hook(events.NewClosure(func(_ int, _ int, _ int, _ iotago.BlockID) {
+		checkpointMilestoneIDs = append(checkpointMilestoneIDs, coo.LatestMilestoneID())
+	}))
+
+	issueDone := make(chan error, 2)
+	go func() {
+		_, err := coo.IssueMilestone(nil)
+		issueDone <- err
+
+		_, err = coo.IssueCheckpoint(0, coo.State().LatestMilestoneBlockID, randBlockIDs(t, 1))
+		issueDone <- err
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-issueDone:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "issuance event handler deadlocked")
+		}
+	}
+	require.Equal(t, []iotago.MilestoneIndex{1}, issuingIndexes)
+	require.Equal(t, []iotago.MilestoneIndex{2}, issuedIndexes)
+	require.NotEmpty(t, activeSigningKeys)
+	require.Equal(t, []iotago.MilestoneID{coo.LatestMilestoneID()}, checkpointMilestoneIDs)
+}
+
+func TestBackPressureSoftErrorEvent(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithMilestoneInterval(time.Millisecond))
+	coo.AddNamedBackPressureFunc("mempool depth", func() bool { return true })
+
+	var softErrorsLock sync.Mutex
+	var softErrors []error
+	coo.Events.SoftError.Hook(events.NewClosure(func(err error) {
+		softErrorsLock.Lock()
+		defer softErrorsLock.Unlock()
+
+		softErrors = append(softErrors, err)
+	}))
+
+	// the event contains the reason of the back pressure
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrNodeLoadTooHigh)
+	require.True(t, coordinator.IsSoft(err))
+	require.True(t, coordinator.SoftErrorEventTriggered(err))
+	require.Len(t, softErrors, 1)
+	require.ErrorIs(t, softErrors[0], coordinator.ErrNodeLoadTooHigh)
+	require.Contains(t, softErrors[0].Error(), "mempool depth")
+
+	// other soft errors are left to the caller
+	coo.Pause()
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorPaused)
+	require.False(t, coordinator.SoftErrorEventTriggered(err))
+	require.Len(t, softErrors, 1)
+	coo.Resume()
+
+	// Run doesn't trigger the event a second time
+	var tipsCalls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- coo.Run(ctx, func() (iotago.BlockIDs, error) {
+			if tipsCalls.Add(1) == 5 {
+				cancel()
+			}
+
+			return nil, nil
+		})
+	}()
+
+	select {
+	case err := <-runDone:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "run didn't stop")
+	}
+
+	softErrorsLock.Lock()
+	defer softErrorsLock.Unlock()
+	require.Len(t, softErrors, 1+int(tipsCalls.Load()))
+}
+
+func TestRemoveBackPressureFunc(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+
+	var databaseSizeCalls, mempoolDepthCalls int
+	coo.AddNamedBackPressureFunc("database size", func() bool {
+		databaseSizeCalls++
+
+		return true
+	})
+	coo.AddNamedBackPressureFunc("mempool depth", func() bool {
+		mempoolDepthCalls++
+
+		return false
+	})
+
+	require.True(t, coo.RemoveBackPressureFunc("database size"))
+	require.False(t, coo.RemoveBackPressureFunc("database size"))
+
+	_, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Zero(t, databaseSizeCalls)
+	require.Equal(t, 1, mempoolDepthCalls)
+
+	// adding a function with an existing name replaces it
+	coo.AddNamedBackPressureFunc("mempool depth", func() bool { return true })
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrNodeLoadTooHigh)
+	require.Equal(t, 1, mempoolDepthCalls)
+}
+
+func TestBackPressureCache(t *testing.T) {
+	clock := &testClock{now: time.Unix(1_000_000, 0)}
+	coo, _ := newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithClock(clock),
+		coordinator.WithBackPressureCacheTTL(10*time.Second),
+		coordinator.WithBackPressureCacheBypassForMilestones(true),
+	)
+
+	calls := 0
+	coo.AddNamedBackPressureFunc("database size", func() bool {
+		calls++
+
+		return false
+	})
+
+	issueCheckpoint := func() {
+		_, err := coo.IssueCheckpoint(0, coo.State().LatestMilestoneBlockID, randBlockIDs(t, 1))
+		require.NoError(t, err)
+	}
+
+	// the result is cached within the TTL
+	issueCheckpoint()
+	issueCheckpoint()
+	require.Equal(t, 1, calls)
+
+	// the cache is bypassed for milestones
+	clock.set(clock.Now().Add(time.Second))
+	_, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	// the cache expires after the TTL
+	clock.set(clock.Now().Add(10 * time.Second))
+	issueCheckpoint()
+	require.Equal(t, 3, calls)
+}
+
+func TestBackPressureTimeout(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithBackPressureTimeout(50*time.Millisecond))
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	coo.AddNamedBackPressureFunc("remote mempool", func() bool {
+		<-unblock
+
+		return false
+	})
+
+	// a blocking function is treated as signaling congestion
+	start := time.Now()
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrNodeLoadTooHigh)
+	require.Contains(t, err.Error(), "remote mempool")
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+
+	// functions that return in time are not affected
+	coo.AddNamedBackPressureFunc("remote mempool", func() bool { return false })
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+}
+
+func TestSetInterval(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil, coordinator.WithMilestoneInterval(10*time.Second))
+	require.Equal(t, 10*time.Second, coo.Interval())
+
+	require.NoError(t, coo.SetInterval(30*time.Second))
+	require.Equal(t, 30*time.Second, coo.Interval())
+
+	// the interval must be positive
+	require.Error(t, coo.SetInterval(0))
+	require.Error(t, coo.SetInterval(-time.Second))
+	require.Equal(t, 30*time.Second, coo.Interval())
+}
+
+func TestIntervalJitter(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil,
+		coordinator.WithMilestoneInterval(10*time.Second),
+		coordinator.WithIntervalJitter(0.2),
+	)
+	require.Equal(t, 10*time.Second, coo.OptionsSnapshot().MilestoneInterval)
+
+	// the jitter is recomputed for every call and stays within the fraction
+	intervals := make(map[time.Duration]struct{})
+	for i := 0; i < 100; i++ {
+		interval := coo.Interval()
+		require.GreaterOrEqual(t, interval, 8*time.Second)
+		require.LessOrEqual(t, interval, 12*time.Second)
+		intervals[interval] = struct{}{}
+	}
+	require.Greater(t, len(intervals), 1)
+
+	// the interval is always positive, even for the largest jitter
+	coo, _ = newTestCoordinator(t, nil,
+		coordinator.WithMilestoneInterval(time.Nanosecond),
+		coordinator.WithIntervalJitter(0.99),
+	)
+	for i := 0; i < 100; i++ {
+		require.Positive(t, coo.Interval())
+	}
+
+	// the fraction must be in [0, 1)
+	for _, fraction := range []float64{-0.1, 1, 1.5} {
+		_, err := coordinator.New(testMerkleRoots, func() bool { return true }, func() *iotago.ProtocolParameters { return testProtoParams }, testSignerProvider(t), nil, nil, (&testBlockSender{}).sendBlock,
+			coordinator.WithIntervalJitter(fraction),
+		)
+		require.Error(t, err)
+		require.True(t, coordinator.IsCritical(err))
+	}
+}
+
+func TestAdaptiveInterval(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithAdaptiveInterval(5*time.Second, 15*time.Second))
+	require.Equal(t, 5*time.Second, coo.Interval())
+
+	// the interval is computed from the back pressure
+	require.Error(t, coo.SetInterval(time.Second))
+
+	congested := true
+	coo.AddNamedBackPressureFunc("test", func() bool { return congested })
+
+	// the interval grows with every congested milestone
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrNodeLoadTooHigh)
+	require.Equal(t, 15*time.Second, coo.Interval())
+
+	// and shrinks back if the node is healthy again
+	congested = false
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, coo.Interval())
+
+	for i := 0; i < 10; i++ {
+		_, err = coo.IssueMilestone(nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 5*time.Second, coo.Interval())
+
+	// min must not exceed max
+	_, err = coordinator.New(testMerkleRoots, nil, nil, testSignerProvider(t), nil, nil, nil, coordinator.WithAdaptiveInterval(10*time.Second, 5*time.Second))
+	require.Error(t, err)
+}
+
+func TestMilestoneMetadata(t *testing.T) {
+	metadata := []byte("version 1.0.0")
+	metadataFunc := func(_ iotago.MilestoneIndex) []byte {
+		return metadata
+	}
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithMilestoneMetadataFunc(metadataFunc))
+
+	milestonePayload, ok := sender.sentBlocks()[0].Payload.(*iotago.Milestone)
+	require.True(t, ok)
+	require.Equal(t, metadata, milestonePayload.Metadata)
+
+	// too long metadata is rejected instead of issuing an invalid milestone
+	metadata = make([]byte, iotago.MaxMetadataLength+1)
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrMilestoneMetadataTooLong)
+	require.Len(t, sender.sentBlocks(), 1)
+}
+
+func TestIssueMilestoneIndexGap(t *testing.T) {
+	var nodeMilestoneIndex iotago.MilestoneIndex
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithLatestMilestoneIndexFunc(func() iotago.MilestoneIndex {
+		return nodeMilestoneIndex
+	}))
+
+	// the node may not know the previous milestone yet
+	_, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+
+	// the node lags behind by more than one milestone, but may catch up
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrMilestoneIndexGap)
+	require.True(t, coordinator.IsSoft(err))
+	require.Equal(t, coordinator.LifecycleStateDegraded, coo.LifecycleState())
+
+	// the node knows milestones the coordinator didn't issue
+	nodeMilestoneIndex = 3
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrMilestoneIndexGap)
+	require.True(t, coordinator.IsCritical(err))
+	require.Len(t, sender.sentBlocks(), 2)
+	coo.ClearReadOnly()
+
+	nodeMilestoneIndex = 2
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, coo.State().LatestMilestoneIndex)
+}
+
+func TestLatestMilestoneGetters(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil)
+	require.Zero(t, coo.LatestMilestoneIndex())
+	require.Equal(t, iotago.EmptyBlockID(), coo.LatestMilestoneBlockID())
+	require.True(t, coo.LatestMilestoneTime().IsZero())
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+
+	blockID, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	state := coo.State()
+	require.EqualValues(t, 2, coo.LatestMilestoneIndex())
+	require.Equal(t, state.LatestMilestoneID, coo.LatestMilestoneID())
+	require.Equal(t, blockID, coo.LatestMilestoneBlockID())
+	require.Equal(t, state.LatestMilestoneTime, coo.LatestMilestoneTime())
+}
+
+func TestHistory(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+	require.Nil(t, coo.History())
+
+	coo, _ = newBootstrappedTestCoordinator(t, nil, coordinator.WithHistorySize(3))
+
+	// the bootstrap milestone is recorded
+	history := coo.History()
+	require.Len(t, history, 1)
+	require.EqualValues(t, 1, history[0].Index)
+
+	blockIDs := make(map[iotago.MilestoneIndex]iotago.BlockID)
+	for i := 0; i < 4; i++ {
+		blockID, err := coo.IssueMilestone(randBlockIDs(t, i))
+		require.NoError(t, err)
+		blockIDs[coo.LatestMilestoneIndex()] = blockID
+	}
+
+	// only the latest milestones are kept, ordered from the oldest to the latest
+	history = coo.History()
+	require.Len(t, history, 3)
+	for i, record := range history {
+		index := iotago.MilestoneIndex(3 + i)
+		require.Equal(t, index, record.Index)
+		require.Equal(t, blockIDs[index], record.BlockID)
+		require.NotZero(t, record.Duration)
+		require.False(t, record.HasReceipt)
+
+		// the previous milestone is added as a parent
+		require.Equal(t, i+2, record.ParentsCount)
+	}
+
+	latest := history[len(history)-1]
+	require.Equal(t, coo.LatestMilestoneID(), latest.MilestoneID)
+	require.Equal(t, coo.LatestMilestoneTime(), latest.Timestamp)
+
+	// the returned history is a copy
+	history[0].Index = 100
+	require.EqualValues(t, 3, coo.History()[0].Index)
+}
+
+func TestHistoryCompaction(t *testing.T) {
+	clock := &testClock{now: time.Unix(1_000_000, 0)}
+	coo, _ := newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithClock(clock),
+		coordinator.WithHistorySize(5),
+		coordinator.WithHistoryCompactAge(90*time.Second),
+	)
+	require.Equal(t, 90*time.Second, coo.OptionsSnapshot().HistoryCompactAge)
+
+	// one milestone per minute
+	for i := 1; i <= 3; i++ {
+		clock.set(time.Unix(1_000_000+int64(i)*60, 0))
+		_, err := coo.IssueMilestone(nil)
+		require.NoError(t, err)
+	}
+
+	// the milestones older than 90 seconds only keep the index and the timestamp
+	history := coo.History()
+	require.Len(t, history, 4)
+	for i, record := range history {
+		require.EqualValues(t, i+1, record.Index)
+		require.Equal(t, time.Unix(1_000_000+int64(i)*60, 0), record.Timestamp)
+
+		if i < 2 {
+			require.True(t, record.Compacted)
+			require.Equal(t, iotago.MilestoneID{}, record.MilestoneID)
+			require.Equal(t, iotago.EmptyBlockID(), record.BlockID)
+			require.Zero(t, record.ParentsCount)
+
+			continue
+		}
+
+		require.False(t, record.Compacted)
+		require.NotEqual(t, iotago.MilestoneID{}, record.MilestoneID)
+		require.Equal(t, 1, record.ParentsCount)
+	}
+	require.Equal(t, coo.LatestMilestoneID(), history[3].MilestoneID)
+
+	// the history is compacted on read even if no further milestone is issued
+	clock.set(time.Unix(1_000_000+10*60, 0))
+	for _, record := range coo.History() {
+		require.True(t, record.Compacted)
+	}
+}
+
+func TestIssuedSinceStart(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil)
+	require.Zero(t, coo.IssuedSinceStart())
+	require.NoError(t, coo.LastIssuanceError())
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, coo.IssuedSinceStart())
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.IssuedSinceStart())
+	require.NoError(t, coo.LastIssuanceError())
+
+	coo.Pause()
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorPaused)
+	require.EqualValues(t, 2, coo.IssuedSinceStart())
+	require.ErrorIs(t, coo.LastIssuanceError(), coordinator.ErrCoordinatorPaused)
+
+	// the last error is kept after a successful milestone
+	coo.Resume()
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, coo.IssuedSinceStart())
+	require.ErrorIs(t, coo.LastIssuanceError(), coordinator.ErrCoordinatorPaused)
+}
+
+func TestNodeSynced(t *testing.T) {
+	var nodeSynced atomic.Bool
+	clock := &testClock{now: time.Unix(1_000_000, 0)}
+
+	coo, err := coordinator.New(
+		testMerkleRoots,
+		nodeSynced.Load,
+		func() *iotago.ProtocolParameters { return testProtoParams },
+		testSignerProvider(t),
+		nil,
+		nil,
+		(&testBlockSender{}).sendBlock,
+		coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state")),
+		coordinator.WithClock(clock),
+	)
+	require.NoError(t, err)
+
+	// the node was not checked yet
+	synced, checkedAt := coo.LastNodeSyncCheck()
+	require.False(t, synced)
+	require.True(t, checkedAt.IsZero())
+
+	require.False(t, coo.NodeSynced())
+	synced, checkedAt = coo.LastNodeSyncCheck()
+	require.False(t, synced)
+	require.Equal(t, clock.Now(), checkedAt)
+
+	// the checks before issuing a milestone are remembered as well
+	nodeSynced.Store(true)
+	clock.set(clock.Now().Add(time.Minute))
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err = coo.Bootstrap()
+	require.NoError(t, err)
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	synced, checkedAt = coo.LastNodeSyncCheck()
+	require.True(t, synced)
+	require.Equal(t, clock.Now(), checkedAt)
+}
+
+func TestHealth(t *testing.T) {
+	clock := &testClock{now: time.Unix(1_000_000, 0)}
+	coo, _ := newTestCoordinator(t, nil, coordinator.WithClock(clock), coordinator.WithMilestoneInterval(10*time.Second))
+
+	_, err := coo.Health()
+	require.ErrorIs(t, err, coordinator.ErrStateNotInitialized)
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err = coo.Bootstrap()
+	require.NoError(t, err)
+
+	health, err := coo.Health()
+	require.NoError(t, err)
+	require.True(t, health.Healthy)
+	require.True(t, health.NodeSynced)
+	require.True(t, health.Bootstrapped)
+	require.False(t, health.Paused)
+	require.False(t, health.ReadOnly)
+	require.False(t, health.Stalled)
+	require.EqualValues(t, 1, health.LatestMilestoneIndex)
+	require.Zero(t, health.TimeSinceLatestMilestone)
+	require.NoError(t, health.LastQuorumErr)
+
+	// no milestone for several intervals
+	clock.set(time.Unix(1_000_031, 0))
+	health, err = coo.Health()
+	require.NoError(t, err)
+	require.False(t, health.Healthy)
+	require.True(t, health.Stalled)
+	require.Equal(t, 31*time.Second, health.TimeSinceLatestMilestone)
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	coo.Pause()
+	health, err = coo.Health()
+	require.NoError(t, err)
+	require.False(t, health.Healthy)
+	require.True(t, health.Paused)
+	require.False(t, health.Stalled)
+}
+
+func TestIssuingMilestoneEvent(t *testing.T) {
+	sender := &testBlockSender{}
+	failSending := false
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if failSending {
+			return iotago.EmptyBlockID(), errors.New("node not reachable")
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	}
+
+	coo, _ := newBootstrappedTestCoordinator(t, sendBlock)
+
+	var issuingIndices []iotago.MilestoneIndex
+	var issuingMilestoneID iotago.MilestoneID
+	var issuingParents iotago.BlockIDs
+	coo.Events.IssuingMilestone.Hook(events.NewClosure(func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, parents iotago.BlockIDs) {
+		// the milestone was not sent yet
+		require.Len(t, sender.sentBlocks(), int(index)-1)
+
+		issuingIndices = append(issuingIndices, index)
+		issuingMilestoneID = milestoneID
+		issuingParents = parents
+	}))
+
+	tips := randBlockIDs(t, 2)
+	_, err := coo.IssueMilestone(tips)
+	require.NoError(t, err)
+	require.Equal(t, coo.State().LatestMilestoneID, issuingMilestoneID)
+	require.Len(t, issuingParents, 3)
+
+	// the event is fired even if sending the milestone fails
+	failSending = true
+	_, err = coo.IssueMilestone(nil)
+	require.Error(t, err)
+	require.Equal(t, []iotago.MilestoneIndex{2, 3}, issuingIndices)
+}
+
+func TestDetachAllEvents(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+
+	issuingCount := 0
+	coo.Events.IssuingMilestone.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, _ iotago.MilestoneID, _ iotago.BlockIDs) {
+		issuingCount++
+	}))
+	issuedCount := 0
+	coo.Events.IssuedMilestone.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, _ iotago.MilestoneID, _ iotago.BlockID) {
+		issuedCount++
+	}))
+	lifecycleChangesCount := 0
+	coo.Events.LifecycleStateChanged.Hook(events.NewClosure(func(_ *coordinator.LifecycleStateChange) {
+		lifecycleChangesCount++
+	}))
+
+	_, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, issuingCount)
+	require.Equal(t, 1, issuedCount)
+
+	coo.DetachAllEvents()
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	coo.Pause()
+
+	// the handlers don't fire anymore
+	require.Equal(t, 1, issuingCount)
+	require.Equal(t, 1, issuedCount)
+	require.Zero(t, lifecycleChangesCount)
+}
+
+func TestInitStatePersistsBootstrapState(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+
+	coo, _ := newTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath))
+	require.NoError(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4, MilestoneID: iotago.MilestoneID{1}}))
+
+	// the coordinator is restarted before the first milestone was issued
+	coo, _ = newTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath))
+	require.ErrorIs(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4, MilestoneID: iotago.MilestoneID{1}}), coordinator.ErrNetworkBootstrapped)
+
+	coo, sender := newTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath))
+	require.NoError(t, coo.InitState(false, 0, &coordinator.LatestMilestoneInfo{Index: 4}))
+	require.True(t, coo.State().PendingBootstrap)
+	require.Equal(t, coordinator.LifecycleStateBootstrapping, coo.LifecycleState())
+
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+	require.Len(t, sender.sentBlocks(), 1)
+
+	milestonePayload, ok := sender.sentBlocks()[0].Payload.(*iotago.Milestone)
+	require.True(t, ok)
+	require.EqualValues(t, 5, milestonePayload.Index)
+	require.Equal(t, iotago.MilestoneID{1}, milestonePayload.PreviousMilestoneID)
+
+	// the bootstrapping is completed
+	coo, _ = newTestCoordinator(t, nil, coordinator.WithStateFilePath(stateFilePath))
+	require.NoError(t, coo.InitState(false, 0, &coordinator.LatestMilestoneInfo{Index: 5}))
+	require.False(t, coo.State().PendingBootstrap)
+	require.Equal(t, coordinator.LifecycleStateRunning, coo.LifecycleState())
+}
+
+func TestBootstrapPreviousMilestoneIDOverride(t *testing.T) {
+	// the previous milestone must not be the genesis without an override
+	coo, _ := newTestCoordinator(t, nil)
+	require.Error(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4}))
+
+	overrideMilestoneID := iotago.MilestoneID{2}
+	coo, sender := newTestCoordinator(t, nil, coordinator.WithBootstrapPreviousMilestoneID(overrideMilestoneID))
+	require.NoError(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4}))
+	require.Equal(t, overrideMilestoneID, coo.State().LatestMilestoneID)
+
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+	require.Len(t, sender.sentBlocks(), 1)
+
+	milestonePayload, ok := sender.sentBlocks()[0].Payload.(*iotago.Milestone)
+	require.True(t, ok)
+	require.Equal(t, overrideMilestoneID, milestonePayload.PreviousMilestoneID)
+
+	// the override takes precedence over the milestone ID of the node
+	coo, _ = newTestCoordinator(t, nil, coordinator.WithBootstrapPreviousMilestoneID(overrideMilestoneID))
+	require.NoError(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4, MilestoneID: iotago.MilestoneID{1}}))
+	require.Equal(t, overrideMilestoneID, coo.State().LatestMilestoneID)
+}
+
+func TestBootstrapParentResolver(t *testing.T) {
+	previousMilestoneID := iotago.MilestoneID{1}
+	previousMilestoneBlockID := randBlockIDs(t, 1)[0]
+	resolver := func(blockID iotago.BlockID) (iotago.MilestoneID, bool, error) {
+		if blockID != previousMilestoneBlockID {
+			return iotago.MilestoneID{}, false, nil
+		}
+
+		return previousMilestoneID, true, nil
+	}
+
+	// the first milestone references the block of the previous milestone
+	coo, sender := newTestCoordinator(t, nil, coordinator.WithBootstrapParentResolver(resolver))
+	require.NoError(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4, MilestoneID: previousMilestoneID, BlockID: previousMilestoneBlockID}))
+
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+	require.Len(t, sender.sentBlocks(), 1)
+	require.Contains(t, sender.sentBlocks()[0].Parents, previousMilestoneBlockID)
+
+	// the parent contains a different milestone
+	coo, sender = newTestCoordinator(t, nil, coordinator.WithBootstrapParentResolver(resolver))
+	require.NoError(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4, MilestoneID: iotago.MilestoneID{2}, BlockID: previousMilestoneBlockID}))
+
+	_, err = coo.Bootstrap()
+	require.ErrorIs(t, err, coordinator.ErrBootstrapParentMismatch)
+	require.NotNil(t, common.IsCriticalError(err))
+	require.Empty(t, sender.sentBlocks())
+
+	// the block of the previous milestone is unknown
+	coo, sender = newTestCoordinator(t, nil, coordinator.WithBootstrapParentResolver(resolver))
+	require.NoError(t, coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4, MilestoneID: previousMilestoneID}))
+
+	_, err = coo.Bootstrap()
+	require.ErrorIs(t, err, coordinator.ErrBootstrapParentMismatch)
+	require.Empty(t, sender.sentBlocks())
+
+	// the check is skipped for a new network
+	coo, sender = newTestCoordinator(t, nil, coordinator.WithBootstrapParentResolver(resolver))
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+
+	_, err = coo.Bootstrap()
+	require.NoError(t, err)
+	require.Len(t, sender.sentBlocks(), 1)
+}
+
+func TestSetMigratorEnabled(t *testing.T) {
+	// without a migrator service the migrator can't be enabled
+	coo, _ := newTestCoordinator(t, nil)
+	require.False(t, coo.MigratorEnabled())
+	coo.SetMigratorEnabled(true)
+	require.False(t, coo.MigratorEnabled())
+
+	sender := &testBlockSender{}
+	coo, err := coordinator.New(
+		testMerkleRoots,
+		func() bool { return true },
+		func() *iotago.ProtocolParameters { return testProtoParams },
+		testSignerProvider(t),
+		migrator.NewService(nil, filepath.Join(t.TempDir(), "migrator.state"), 10),
+		func() (*coordinator.LatestTreasuryOutput, error) {
+			return nil, errors.New("treasury output must not be fetched")
+		},
+		sender.sendBlock,
+		coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state")),
+	)
+	require.NoError(t, err)
+	require.True(t, coo.MigratorEnabled())
+
+	coo.SetMigratorEnabled(false)
+	require.False(t, coo.MigratorEnabled())
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err = coo.Bootstrap()
+	require.NoError(t, err)
+
+	coo.SetMigratorEnabled(true)
+	require.True(t, coo.MigratorEnabled())
+
+	// no migrations are pending, so no receipt is included
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Len(t, sender.sentBlocks(), 2)
+}
+
+// testMigrationsQueryer returns the same migrated funds for a single legacy milestone
+// and blocks on further queries until it is closed.
+type testMigrationsQueryer struct {
+	migratedAt iotago.MilestoneIndex
+	entries    []*iotago.MigratedFundsEntry
+	closed     chan struct{}
+}
+
+func (q *testMigrationsQueryer) QueryMigratedFunds(msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	if msIndex == q.migratedAt {
+		return q.entries, nil
+	}
+
+	return nil, nil
+}
+
+func (q *testMigrationsQueryer) QueryNextMigratedFunds(_ iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	<-q.closed
+
+	return 0, nil, errors.New("queryer closed")
+}
+
+// newMigratorTestCoordinator creates a bootstrapped test coordinator with a running migrator service,
+// which provides a single receipt migrating 3 Mi out of a treasury of 10 Mi.
+func newMigratorTestCoordinator(t *testing.T, opts ...coordinator.Option) (*coordinator.Coordinator, *testBlockSender) {
+	t.Helper()
+
+	return newMigratorTestCoordinatorWithTreasury(t, 10, func() (*coordinator.LatestTreasuryOutput, error) {
+		return &coordinator.LatestTreasuryOutput{MilestoneID: iotago.MilestoneID{1}, Amount: 10_000_000}, nil
+	}, opts...)
+}
+
+// newMigratorTestCoordinatorWithTreasury creates a bootstrapped test coordinator with a running migrator service,
+// which migrates 3 Mi in receipts of up to receiptMaxEntries entries of 1 Mi each.
+func newMigratorTestCoordinatorWithTreasury(t *testing.T, receiptMaxEntries int, treasuryOutputFunc coordinator.UnspentTreasuryOutputFunc, opts ...coordinator.Option) (*coordinator.Coordinator, *testBlockSender) {
+	t.Helper()
+
+	queryer := &testMigrationsQueryer{
+		migratedAt: 1,
+		closed:     make(chan struct{}),
+	}
+	for i := byte(0); i < 3; i++ {
+		queryer.entries = append(queryer.entries, &iotago.MigratedFundsEntry{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{i},
+			Address:             &iotago.Ed25519Address{i},
+			Deposit:             1_000_000,
+		})
+	}
+
+	migratorService := migrator.NewService(queryer, filepath.Join(t.TempDir(), "migrator.state"), receiptMaxEntries)
+	require.NoError(t, migratorService.InitState(&queryer.migratedAt))
+
+	sender := &testBlockSender{}
+	opts = append([]coordinator.Option{coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state"))}, opts...)
+	coo, err := coordinator.New(
+		testMerkleRoots,
+		func() bool { return true },
+		func() *iotago.ProtocolParameters { return testProtoParams },
+		testSignerProvider(t),
+		migratorService,
+		treasuryOutputFunc,
+		sender.sendBlock,
+		opts...,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err = coo.Bootstrap()
+	require.NoError(t, err)
+
+	// the migrator service is started after the bootstrap, so the first milestone doesn't contain the receipt
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		close(queryer.closed)
+	})
+	go migratorService.Start(ctx, func(_ error) bool { return false })
+
+	return coo, sender
+}
+
+func TestReceiptValidator(t *testing.T) {
+	var validatedTransaction *iotago.TreasuryTransaction
+	coo, sender := newMigratorTestCoordinator(t, coordinator.WithReceiptValidator(func(receipt *iotago.ReceiptMilestoneOpt, transaction *iotago.TreasuryTransaction) error {
+		require.Same(t, receipt.Transaction, transaction)
+		validatedTransaction = transaction
+
+		return nil
+	}))
+
+	// the receipt is available as soon as the migrator service fetched the migrated funds.
+	// milestones are issued one after another, require.Eventually could issue them concurrently.
+	for deadline := time.Now().Add(5 * time.Second); validatedTransaction == nil; time.Sleep(10 * time.Millisecond) {
+		require.True(t, time.Now().Before(deadline), "no receipt was validated")
+
+		_, err := coo.IssueMilestone(nil)
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 7_000_000, validatedTransaction.Output.Amount)
+
+	sentBlocks := sender.sentBlocks()
+	milestonePayload, ok := sentBlocks[len(sentBlocks)-1].Payload.(*iotago.Milestone)
+	require.True(t, ok)
+	require.Len(t, milestonePayload.Opts, 1)
+
+	// a rejected receipt aborts the issuance
+	validatorErr := errors.New("invariant violated")
+	coo, sender = newMigratorTestCoordinator(t, coordinator.WithReceiptValidator(func(_ *iotago.ReceiptMilestoneOpt, _ *iotago.TreasuryTransaction) error {
+		return validatorErr
+	}))
+
+	var err error
+	for deadline := time.Now().Add(5 * time.Second); err == nil; time.Sleep(10 * time.Millisecond) {
+		require.True(t, time.Now().Before(deadline), "no receipt was rejected")
+
+		_, err = coo.IssueMilestone(nil)
+	}
+	require.ErrorIs(t, err, validatorErr)
+	require.NotNil(t, common.IsCriticalError(err))
+
+	// no milestone with a receipt was sent
+	for _, block := range sender.sentBlocks() {
+		milestonePayload, ok := block.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		require.Empty(t, milestonePayload.Opts)
+	}
+}
+
+// issueMilestonesUntilReceipts issues milestones until the given amount of receipts was issued or an error occurred.
+// Returns the receipts and the error.
+func issueMilestonesUntilReceipts(t *testing.T, coo *coordinator.Coordinator, sender *testBlockSender, receiptsCount int) ([]*iotago.ReceiptMilestoneOpt, error) {
+	t.Helper()
+
+	var receipts []*iotago.ReceiptMilestoneOpt
+	for deadline := time.Now().Add(5 * time.Second); len(receipts) < receiptsCount; time.Sleep(10 * time.Millisecond) {
+		require.True(t, time.Now().Before(deadline), "not enough receipts were issued")
+
+		if _, err := coo.IssueMilestone(nil); err != nil {
+			return receipts, err
+		}
+
+		sentBlocks := sender.sentBlocks()
+		milestonePayload, ok := sentBlocks[len(sentBlocks)-1].Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		if receipt := milestonePayload.Opts.MustSet().Receipt(); receipt != nil {
+			receipts = append(receipts, receipt)
+		}
+	}
+
+	return receipts, nil
+}
+
+func TestStaleTreasuryOutput(t *testing.T) {
+	// the treasury output never reflects the issued receipts
+	coo, sender := newMigratorTestCoordinatorWithTreasury(t, 2, func() (*coordinator.LatestTreasuryOutput, error) {
+		return &coordinator.LatestTreasuryOutput{MilestoneID: iotago.MilestoneID{1}, Amount: 10_000_000}, nil
+	})
+
+	receipts, err := issueMilestonesUntilReceipts(t, coo, sender, 2)
+	require.ErrorIs(t, err, coordinator.ErrTreasuryOutputStale)
+	require.True(t, coordinator.IsCritical(err))
+	require.Len(t, receipts, 1)
+	require.EqualValues(t, 8_000_000, receipts[0].Transaction.Output.Amount)
+}
+
+func TestUpToDateTreasuryOutput(t *testing.T) {
+	var sender *testBlockSender
+	var coo *coordinator.Coordinator
+	// the treasury output is created by the latest milestone with a receipt
+	coo, sender = newMigratorTestCoordinatorWithTreasury(t, 2, func() (*coordinator.LatestTreasuryOutput, error) {
+		treasuryOutput := &coordinator.LatestTreasuryOutput{MilestoneID: iotago.MilestoneID{1}, Amount: 10_000_000}
+		for _, block := range sender.sentBlocks() {
+			milestonePayload, ok := block.Payload.(*iotago.Milestone)
+			require.True(t, ok)
+
+			if receipt := milestonePayload.Opts.MustSet().Receipt(); receipt != nil {
+				milestoneID, err := milestonePayload.ID()
+				require.NoError(t, err)
+				treasuryOutput = &coordinator.LatestTreasuryOutput{MilestoneID: milestoneID, Amount: receipt.Transaction.Output.Amount}
+			}
+		}
+
+		return treasuryOutput, nil
+	})
+
+	receipts, err := issueMilestonesUntilReceipts(t, coo, sender, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 8_000_000, receipts[0].Transaction.Output.Amount)
+	require.EqualValues(t, 7_000_000, receipts[1].Transaction.Output.Amount)
+}
+
+func TestLastQuorumResult(t *testing.T) {
+	var quorumAvailable atomic.Bool
+	quorumAvailable.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !quorumAvailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		emptyRoot := iotago.EncodeHex(make([]byte, iotago.MilestoneMerkleProofLength))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"inclusionMerkleRoot":"` + emptyRoot + `","appliedMerkleRoot":"` + emptyRoot + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	clock := &testClock{now: time.Unix(1_000_000, 0)}
+	coo, _ := newTestCoordinator(t, nil,
+		coordinator.WithClock(clock),
+		coordinator.WithQuorum(true, map[string][]*coordinator.QuorumClientConfig{
+			"group": {{BaseURL: server.URL}},
+		}, time.Second),
+	)
+	require.Nil(t, coo.LastQuorumResult())
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+
+	result := coo.LastQuorumResult()
+	require.NotNil(t, result)
+	require.NoError(t, result.Err)
+	require.Equal(t, time.Unix(1_000_000, 0), result.Timestamp)
+
+	quorumAvailable.Store(false)
+	clock.set(time.Unix(1_000_010, 0))
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrQuorumGroupNoAnswer)
+
+	result = coo.LastQuorumResult()
+	require.NotNil(t, result)
+	require.ErrorIs(t, result.Err, coordinator.ErrQuorumGroupNoAnswer)
+	require.Equal(t, time.Unix(1_000_010, 0), result.Timestamp)
+}
+
+func TestRun(t *testing.T) {
+	coo, sender := newTestCoordinator(t, nil, coordinator.WithMilestoneInterval(10*time.Millisecond))
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+
+	var softErrorsLock sync.Mutex
+	var softErrors []error
+	coo.Events.SoftError.Hook(events.NewClosure(func(err error) {
+		softErrorsLock.Lock()
+		defer softErrorsLock.Unlock()
+
+		softErrors = append(softErrors, err)
+	}))
+
+	var tipsCalls atomic.Int32
+	tipsFunc := func() (iotago.BlockIDs, error) {
+		if tipsCalls.Add(1) == 1 {
+			return nil, errors.New("no tips available")
+		}
+
+		return randBlockIDs(t, 2), nil
+	}
+
+	// pausing results in soft errors until the coordinator is resumed
+	coo.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- coo.Run(ctx, tipsFunc)
+	}()
+
+	require.Eventually(t, func() bool {
+		softErrorsLock.Lock()
+		defer softErrorsLock.Unlock()
+
+		return len(softErrors) > 0
+	}, 5*time.Second, 5*time.Millisecond)
+
+	softErrorsLock.Lock()
+	require.ErrorIs(t, softErrors[0], coordinator.ErrCoordinatorPaused)
+	softErrorsLock.Unlock()
+
+	// the network is bootstrapped, but no milestones are issued while paused
+	require.Len(t, sender.sentBlocks(), 1)
+
+	coo.Resume()
+	require.Eventually(t, func() bool {
+		return len(sender.sentBlocks()) >= 3
+	}, 5*time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-runDone)
+}
+
+func TestRunStopsOnCriticalError(t *testing.T) {
+	coo, sender := newTestCoordinator(t, nil,
+		coordinator.WithMilestoneInterval(time.Millisecond),
+		coordinator.WithLatestMilestoneIndexFunc(func() iotago.MilestoneIndex {
+			// the node knows milestones the coordinator didn't issue
+			return 5
+		}),
+	)
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+
+	err := coo.Run(context.Background(), func() (iotago.BlockIDs, error) {
+		return randBlockIDs(t, 2), nil
+	})
+	require.ErrorIs(t, err, coordinator.ErrMilestoneIndexGap)
+	require.True(t, coordinator.IsCritical(err))
+	require.Len(t, sender.sentBlocks(), 1)
+}
+
+func TestOptionsSnapshot(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+	coo, _ := newTestCoordinator(t, nil,
+		coordinator.WithStateFilePath(stateFilePath),
+		coordinator.WithSigningRetryAmount(3),
+		coordinator.WithMaxParentsCount(4),
+	)
+
+	options := coo.OptionsSnapshot()
+	require.Equal(t, stateFilePath, options.StateFilePath)
+	require.Equal(t, 3, options.SigningRetryAmount)
+	require.Equal(t, 4, options.MaxParentsCount)
+	require.False(t, options.QuorumEnabled)
+	require.False(t, options.MigratorEnabled)
+	require.False(t, options.CrashRecoveryEnabled)
+
+	// defaults are applied
+	require.Equal(t, 2*time.Second, options.SigningRetryTimeout)
+	require.Equal(t, 1, options.SendBlockRetryAttempts)
+
+	// the interval reflects changes at runtime
+	require.NoError(t, coo.SetInterval(time.Minute))
+	require.Equal(t, time.Minute, coo.OptionsSnapshot().MilestoneInterval)
+}
+
+func TestInvalidQuorumConfig(t *testing.T) {
+	newCoordinatorWithQuorum := func(quorumEnabled bool, quorumGroups map[string][]*coordinator.QuorumClientConfig) error {
+		_, err := coordinator.New(testMerkleRoots, nil, nil, testSignerProvider(t), nil, nil, nil,
+			coordinator.WithQuorum(quorumEnabled, quorumGroups, time.Second),
+		)
+
+		return err
+	}
+
+	err := newCoordinatorWithQuorum(true, nil)
+	require.ErrorIs(t, err, coordinator.ErrQuorumGroupsNotFound)
+	require.Error(t, common.IsCriticalError(err))
+
+	err = newCoordinatorWithQuorum(true, map[string][]*coordinator.QuorumClientConfig{
+		"group": {},
+	})
+	require.ErrorIs(t, err, coordinator.ErrQuorumGroupWithoutNodes)
+	require.ErrorContains(t, err, "group")
+
+	err = newCoordinatorWithQuorum(true, map[string][]*coordinator.QuorumClientConfig{
+		"group": {nil},
+	})
+	require.Error(t, err)
+
+	// the groups are ignored if the quorum is disabled
+	require.NoError(t, newCoordinatorWithQuorum(false, nil))
+
+	// the weight threshold must be reachable by every group
+	_, err = coordinator.New(testMerkleRoots, nil, nil, testSignerProvider(t), nil, nil, nil,
+		coordinator.WithQuorum(true, map[string][]*coordinator.QuorumClientConfig{
+			"group": {{BaseURL: "http://node1:14265", Weight: 2}},
+		}, time.Second),
+		coordinator.WithQuorumWeightThreshold(3),
+	)
+	require.ErrorIs(t, err, coordinator.ErrQuorumWeightThresholdUnreachable)
+	require.Error(t, common.IsCriticalError(err))
+
+	coo, _ := newTestCoordinator(t, nil)
+	require.ErrorIs(t, coo.UpdateQuorum(nil, time.Second), coordinator.ErrQuorumGroupsNotFound)
+
+	coo, _ = newTestCoordinator(t, nil, coordinator.WithQuorumWeightThreshold(2))
+	require.ErrorIs(t, coo.UpdateQuorum(map[string][]*coordinator.QuorumClientConfig{
+		"group": {{BaseURL: "http://node1:14265"}},
+	}, time.Second), coordinator.ErrQuorumWeightThresholdUnreachable)
+}
+
+func TestMinMilestoneInterval(t *testing.T) {
+	clock := &testClock{now: time.Unix(1_000_000, 0)}
+	coo, sender := newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithClock(clock),
+		coordinator.WithMinMilestoneInterval(5*time.Second),
+	)
+
+	// the second milestone is issued right after the bootstrap milestone
+	clock.set(time.Unix(1_000_001, 0))
+	ready, reasons := coo.Readiness()
+	require.False(t, ready)
+	require.Equal(t, []string{"milestone too early, 1s after the previous milestone, minimum interval: 5s"}, reasons)
+
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrMilestoneTooEarly)
+	require.Error(t, common.IsSoftError(err))
+	require.Len(t, sender.sentBlocks(), 1)
+
+	clock.set(time.Unix(1_000_005, 0))
+	ready, reasons = coo.Readiness()
+	require.True(t, ready, reasons)
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Len(t, sender.sentBlocks(), 2)
+
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrMilestoneTooEarly)
+	require.Len(t, sender.sentBlocks(), 2)
+}
+
+func BenchmarkIssueCheckpoint(b *testing.B) {
+	for _, tipsCount := range []int{56, 128, 512} {
+		b.Run(fmt.Sprintf("%d tips", tipsCount), func(b *testing.B) {
+			coo, _ := newTestCoordinator(b, func(block *iotago.Block, _ ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+				return block.ID()
+			})
+
+			lastCheckpointBlockID := randBlockIDs(b, 1)[0]
+			tips := randBlockIDs(b, tipsCount)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := coo.IssueCheckpoint(i, lastCheckpointBlockID, tips); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestPendingProtocolParameterUpdate(t *testing.T) {
+	newProtoParams := *testProtoParams
+	newProtoParams.Version = 3
+	newProtoParams.MinPoWScore = 1000
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithPendingProtocolParameterUpdate(3, &newProtoParams))
+
+	for i := 0; i < 3; i++ {
+		_, err := coo.IssueMilestone(nil)
+		require.NoError(t, err)
+	}
+
+	sentBlocks := sender.sentBlocks()
+	require.Len(t, sentBlocks, 4)
+
+	// only the milestone at the target index contains the update
+	for _, block := range sentBlocks {
+		milestonePayload, ok := block.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+
+		protoParamsOpt := milestonePayload.Opts.MustSet().ProtocolParams()
+		if milestonePayload.Index != 3 {
+			require.Nil(t, protoParamsOpt)
+
+			continue
+		}
+
+		require.NotNil(t, protoParamsOpt)
+		require.EqualValues(t, 3, protoParamsOpt.TargetMilestoneIndex)
+		require.EqualValues(t, 3, protoParamsOpt.ProtocolVersion)
+
+		protoParams := &iotago.ProtocolParameters{}
+		_, err := protoParams.Deserialize(protoParamsOpt.Params, serializer.DeSeriModePerformValidation, nil)
+		require.NoError(t, err)
+		require.Equal(t, &newProtoParams, protoParams)
+	}
+}
+
+func TestPendingProtocolParameterUpdateInvalid(t *testing.T) {
+	newTestCoordinatorWithUpdate := func(latestMilestoneIndex iotago.MilestoneIndex, targetIndex iotago.MilestoneIndex, params *iotago.ProtocolParameters) error {
+		_, err := coordinator.New(
+			testMerkleRoots,
+			func() bool { return true },
+			func() *iotago.ProtocolParameters { return testProtoParams },
+			testSignerProvider(t),
+			nil,
+			nil,
+			(&testBlockSender{}).sendBlock,
+			coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state")),
+			coordinator.WithLatestMilestoneIndexFunc(func() iotago.MilestoneIndex { return latestMilestoneIndex }),
+			coordinator.WithPendingProtocolParameterUpdate(targetIndex, params),
+		)
+
+		return err
+	}
+
+	require.ErrorIs(t, newTestCoordinatorWithUpdate(5, 10, nil), coordinator.ErrInvalidProtocolParametersUpdate)
+	require.ErrorIs(t, newTestCoordinatorWithUpdate(5, 0, testProtoParams), coordinator.ErrInvalidProtocolParametersUpdate)
+	require.ErrorIs(t, newTestCoordinatorWithUpdate(5, 5, testProtoParams), coordinator.ErrInvalidProtocolParametersUpdate)
+	require.NoError(t, newTestCoordinatorWithUpdate(5, 6, testProtoParams))
+
+	// the target index is checked against the coordinator state if the node index is unknown
+	coo, _ := newTestCoordinator(t, nil, coordinator.WithPendingProtocolParameterUpdate(4, testProtoParams))
+	err := coo.InitState(true, 5, &coordinator.LatestMilestoneInfo{Index: 4, MilestoneID: iotago.MilestoneID{1}})
+	require.ErrorIs(t, err, coordinator.ErrInvalidProtocolParametersUpdate)
+}
+
+func TestQuorumTopology(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil)
+	require.Nil(t, coo.QuorumTopology())
+
+	coo, _ = newTestCoordinator(t, nil,
+		coordinator.WithQuorum(true, map[string][]*coordinator.QuorumClientConfig{
+			"group1": {
+				{Alias: "node1", BaseURL: "http://node1:14265", Weight: 2},
+				{Alias: "node2", BaseURL: "http://node2:14265", Timeout: 5 * time.Second},
+			},
+			"group2": {
+				{BaseURL: "http://node3:14265"},
+			},
+		}, time.Second),
+	)
+
+	// the topology is known before the first quorum check
+	require.Nil(t, coo.LastQuorumResult())
+	require.Equal(t, map[string][]coordinator.QuorumGroupMember{
+		"group1": {
+			{Alias: "node1", BaseURL: "http://node1:14265", Weight: 2, Timeout: time.Second},
+			{Alias: "node2", BaseURL: "http://node2:14265", Weight: 1, Timeout: 5 * time.Second},
+		},
+		"group2": {
+			{BaseURL: "http://node3:14265", Weight: 1, Timeout: time.Second},
+		},
+	}, coo.QuorumTopology())
+
+	// the topology is a copy
+	coo.QuorumTopology()["group1"][0].Alias = "changed"
+	require.Equal(t, "node1", coo.QuorumTopology()["group1"][0].Alias)
+
+	require.NoError(t, coo.UpdateQuorum(map[string][]*coordinator.QuorumClientConfig{
+		"group3": {{BaseURL: "http://node4:14265"}},
+	}, time.Second))
+	require.Equal(t, map[string][]coordinator.QuorumGroupMember{
+		"group3": {{BaseURL: "http://node4:14265", Weight: 1, Timeout: time.Second}},
+	}, coo.QuorumTopology())
+}
+
+func TestIssueMilestoneUnsortedParents(t *testing.T) {
+	var whiteFlagParents iotago.BlockIDs
+	sender := &testBlockSender{}
+	coo, err := coordinator.New(
+		func(_ context.Context, _ iotago.MilestoneIndex, _ uint32, parents iotago.BlockIDs, _ iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+			whiteFlagParents = append(iotago.BlockIDs{}, parents...)
+
+			return &coordinator.MilestoneMerkleRoots{}, nil
+		},
+		func() bool { return true },
+		func() *iotago.ProtocolParameters { return testProtoParams },
+		testSignerProvider(t),
+		nil,
+		nil,
+		sender.sendBlock,
+		coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state")),
+	)
+	require.NoError(t, err)
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err = coo.Bootstrap()
+	require.NoError(t, err)
+
+	// the tips are intentionally unsorted and contain duplicates
+	tips := iotago.BlockIDs{{9}, {3}, {7}, {3}, {1}, {9}}
+	_, err = coo.IssueMilestone(tips)
+	require.NoError(t, err)
+
+	sentBlocks := sender.sentBlocks()
+	milestoneBlock := sentBlocks[len(sentBlocks)-1]
+	milestonePayload, ok := milestoneBlock.Payload.(*iotago.Milestone)
+	require.True(t, ok)
+
+	// the white flag computation and the milestone use the same sorted parents
+	require.Len(t, whiteFlagParents, 5)
+	require.Equal(t, append(iotago.BlockIDs{}, whiteFlagParents...).RemoveDupsAndSort(), whiteFlagParents)
+	require.Equal(t, whiteFlagParents, milestonePayload.Parents)
+	require.Equal(t, whiteFlagParents, milestoneBlock.Parents)
+}
+
+func TestMerkleComputeTimeout(t *testing.T) {
+	var hung atomic.Bool
+	coo, err := coordinator.New(
+		func(ctx context.Context, _ iotago.MilestoneIndex, _ uint32, _ iotago.BlockIDs, _ iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+			if hung.Load() {
+				// the node doesn't answer until the request is cancelled
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			}
+
+			return &coordinator.MilestoneMerkleRoots{}, nil
+		},
+		func() bool { return true },
+		func() *iotago.ProtocolParameters { return testProtoParams },
+		testSignerProvider(t),
+		nil,
+		nil,
+		(&testBlockSender{}).sendBlock,
+		coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state")),
+		coordinator.WithMerkleComputeTimeout(50*time.Millisecond),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 50*time.Millisecond, coo.OptionsSnapshot().MerkleComputeTimeout)
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err = coo.Bootstrap()
+	require.NoError(t, err)
+
+	hung.Store(true)
+	start := time.Now()
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrMerkleComputeTimeout)
+	require.True(t, coordinator.IsCritical(err))
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+}
+
+func TestIssueMilestoneStrict(t *testing.T) {
+	parents := randBlockIDs(t, 3)
+	notSolid := map[iotago.BlockID]struct{}{
+		parents[1]: {},
+	}
+	coo, sender := newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithBlockSolidFunc(func(blockID iotago.BlockID) (bool, error) {
+			_, exists := notSolid[blockID]
+
+			return !exists, nil
+		}),
+	)
+
+	// the non-solid parent is listed in the error
+	_, err := coo.IssueMilestoneStrict(parents, true)
+	require.ErrorIs(t, err, coordinator.ErrParentsNotSolid)
+	require.True(t, coordinator.IsSoft(err))
+	require.Contains(t, err.Error(), parents[1].ToHex())
+	require.NotContains(t, err.Error(), parents[0].ToHex())
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+	require.Len(t, sender.sentBlocks(), 1)
+
+	// the solidity is not checked if it is not required
+	_, err = coo.IssueMilestoneStrict(parents, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+
+	// the milestone is issued once all parents are solid
+	delete(notSolid, parents[1])
+	_, err = coo.IssueMilestoneStrict(parents, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, coo.State().LatestMilestoneIndex)
+}
+
+func TestIssueMilestoneStrictErrors(t *testing.T) {
+	parents := randBlockIDs(t, 2)
+
+	// solid parents can't be required without a BlockSolidFunc
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+	_, err := coo.IssueMilestoneStrict(parents, true)
+	require.ErrorIs(t, err, coordinator.ErrBlockSolidFuncMissing)
+	require.True(t, coordinator.IsCritical(err))
+
+	// a failing solidity check is retried with the next milestone
+	errSolidity := errors.New("node unavailable")
+	coo, _ = newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithBlockSolidFunc(func(_ iotago.BlockID) (bool, error) {
+			return false, errSolidity
+		}),
+	)
+	_, err = coo.IssueMilestoneStrict(parents, true)
+	require.ErrorIs(t, err, errSolidity)
+	require.True(t, coordinator.IsSoft(err))
+	require.EqualValues(t, 1, coo.State().LatestMilestoneIndex)
+}
+
+func TestInMemorySignerProvider(t *testing.T) {
+	privateKeys := make([]ed25519.PrivateKey, 3)
+	for i := range privateKeys {
+		_, privKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		privateKeys[i] = privKey
+	}
+
+	signerProvider := coordinator.NewInMemorySignerProvider(privateKeys...)
+	require.Equal(t, 3, signerProvider.PublicKeysCount())
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithSignerProvider(signerProvider))
+
+	// every milestone is signed with all keys
+	_, err := coo.IssueMilestone(randBlockIDs(t, 2))
+	require.NoError(t, err)
+
+	for _, block := range sender.sentBlocks() {
+		milestonePayload, ok := block.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		require.Len(t, milestonePayload.Signatures, 3)
+
+		signer := signerProvider.MilestoneIndexSigner(milestonePayload.Index)
+		require.NoError(t, milestonePayload.VerifySignatures(3, signer.PublicKeysSet()))
+	}
+}
+
+func TestIssueMilestoneWithID(t *testing.T) {
+	coo, sender := newBootstrappedTestCoordinator(t, nil)
+
+	blockID, milestoneID, err := coo.IssueMilestoneWithID(randBlockIDs(t, 2))
+	require.NoError(t, err)
+	require.Equal(t, coo.State().LatestMilestoneBlockID, blockID)
+	require.Equal(t, coo.State().LatestMilestoneID, milestoneID)
+
+	sentBlocks := sender.sentBlocks()
+	milestonePayload, ok := sentBlocks[len(sentBlocks)-1].Payload.(*iotago.Milestone)
+	require.True(t, ok)
+	expectedMilestoneID, err := milestonePayload.ID()
+	require.NoError(t, err)
+	require.Equal(t, expectedMilestoneID, milestoneID)
+
+	// no IDs are returned if the milestone is not issued
+	coo.Pause()
+	blockID, milestoneID, err = coo.IssueMilestoneWithID(nil)
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorPaused)
+	require.Equal(t, iotago.EmptyBlockID(), blockID)
+	require.Equal(t, iotago.MilestoneID{}, milestoneID)
+}
+
+func TestStateBackups(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+	coo, _ := newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithStateFilePath(stateFilePath),
+		coordinator.WithStateBackups(2),
+	)
+	require.Equal(t, 2, coo.OptionsSnapshot().StateBackups)
+
+	for i := 0; i < 4; i++ {
+		_, err := coo.IssueMilestone(randBlockIDs(t, 1))
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 5, coo.State().LatestMilestoneIndex)
+
+	readBackup := func(number int) *coordinator.State {
+		state := &coordinator.State{}
+		require.NoError(t, ioutils.ReadJSONFromFile(fmt.Sprintf("%s.%d", stateFilePath, number), state))
+
+		return state
+	}
+
+	// the most recent backup contains the state before the latest milestone
+	require.EqualValues(t, 4, readBackup(1).LatestMilestoneIndex)
+	require.EqualValues(t, 3, readBackup(2).LatestMilestoneIndex)
+
+	// older backups are dropped
+	_, err := os.Stat(fmt.Sprintf("%s.3", stateFilePath))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(fmt.Sprintf("%s.1.tmp", stateFilePath))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestStateTransitionObserver(t *testing.T) {
+	type stateTransition struct {
+		oldState coordinator.State
+		newState coordinator.State
+	}
+
+	var transitions []stateTransition
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithStateTransitionObserver(func(oldState coordinator.State, newState coordinator.State) {
+		transitions = append(transitions, stateTransition{oldState: oldState, newState: newState})
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, err := coo.IssueMilestone(randBlockIDs(t, 1))
+		require.NoError(t, err)
+	}
+
+	// the bootstrap milestone is observed as well
+	require.Len(t, transitions, 3)
+	require.True(t, transitions[0].oldState.PendingBootstrap)
+	require.False(t, transitions[0].newState.PendingBootstrap)
+
+	sentBlocks := sender.sentBlocks()
+	require.Len(t, sentBlocks, 3)
+	for i, transition := range transitions {
+		// the transitions form a chain
+		if i > 0 {
+			require.Equal(t, transitions[i-1].newState, transition.oldState)
+		}
+		require.Equal(t, transition.oldState.LatestMilestoneIndex+1, transition.newState.LatestMilestoneIndex)
+
+		sentBlockID, err := sentBlocks[i].ID()
+		require.NoError(t, err)
+		require.Equal(t, sentBlockID, transition.newState.LatestMilestoneBlockID)
+
+		milestonePayload, ok := sentBlocks[i].Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		require.Equal(t, transition.oldState.LatestMilestoneID, milestonePayload.PreviousMilestoneID)
+	}
+	require.Equal(t, *coo.State(), transitions[2].newState)
+}
+
+func TestMaxCheckpointsPerInterval(t *testing.T) {
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithMaxCheckpointsPerInterval(2))
+	require.Equal(t, 2, coo.OptionsSnapshot().MaxCheckpointsPerInterval)
+
+	issueCheckpoint := func(checkpointIndex int) error {
+		_, err := coo.IssueCheckpoint(checkpointIndex, coo.State().LatestMilestoneBlockID, randBlockIDs(t, 1))
+
+		return err
+	}
+
+	require.NoError(t, issueCheckpoint(0))
+	require.NoError(t, issueCheckpoint(1))
+
+	// further checkpoints are rejected until the next milestone
+	err := issueCheckpoint(2)
+	require.ErrorIs(t, err, coordinator.ErrCheckpointLimitReached)
+	require.True(t, coordinator.IsSoft(err))
+	require.Len(t, sender.sentBlocks(), 3)
+
+	// the limit only holds checkpoints, the coordinator is still ready to issue the milestone that resets it
+	ready, reasons := coo.Readiness()
+	require.True(t, ready, reasons)
+
+	// the limit is reset by the next milestone
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.NoError(t, issueCheckpoint(0))
+}
+
+func TestShouldForceMilestone(t *testing.T) {
+	const forceMilestoneAfterCheckpoints = 3
+
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithForceMilestoneAfterCheckpoints(forceMilestoneAfterCheckpoints))
+
+	issueCheckpoints := func(count int) {
+		for i := 0; i < count; i++ {
+			_, err := coo.IssueCheckpoint(i, coo.State().LatestMilestoneBlockID, randBlockIDs(t, 1))
+			require.NoError(t, err)
+		}
+	}
+
+	issueCheckpoints(forceMilestoneAfterCheckpoints)
+	require.False(t, coo.ShouldForceMilestone())
+
+	// a milestone is forced as soon as more checkpoints were issued
+	issueCheckpoints(1)
+	require.True(t, coo.ShouldForceMilestone())
+
+	// the counter is reset by the next milestone
+	_, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.False(t, coo.ShouldForceMilestone())
+
+	issueCheckpoints(forceMilestoneAfterCheckpoints + 1)
+	require.True(t, coo.ShouldForceMilestone())
+
+	// a milestone is never forced without the option
+	coo, _ = newBootstrappedTestCoordinator(t, nil)
+	issueCheckpoints(10)
+	require.False(t, coo.ShouldForceMilestone())
+}
+
+func TestLastIssuanceTiming(t *testing.T) {
+	errSend := errors.New("node unavailable")
+	var failSend atomic.Bool
+	sender := &testBlockSender{}
+	coo, _ := newTestCoordinator(t, func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if failSend.Load() {
+			return iotago.EmptyBlockID(), errSend
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	})
+	require.Nil(t, coo.LastIssuanceTiming())
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	_, err := coo.Bootstrap()
+	require.NoError(t, err)
+
+	timing := coo.LastIssuanceTiming()
+	require.NotNil(t, timing)
+	require.EqualValues(t, 1, timing.MilestoneIndex)
+	require.NoError(t, timing.Err)
+	require.Positive(t, timing.Signing)
+	require.Positive(t, timing.Persist)
+	// the quorum is disabled
+	require.Zero(t, timing.Quorum)
+	require.GreaterOrEqual(t, timing.Total, timing.Merkle+timing.Signing+timing.Send+timing.Persist)
+
+	// the phases that didn't complete are not set
+	failSend.Store(true)
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, errSend)
+
+	timing = coo.LastIssuanceTiming()
+	require.EqualValues(t, 2, timing.MilestoneIndex)
+	require.ErrorIs(t, timing.Err, errSend)
+	require.Positive(t, timing.Signing)
+	require.Zero(t, timing.Send)
+	require.Zero(t, timing.Persist)
+}
+
+func TestIssueMilestoneExpecting(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+	previousMilestoneID := coo.State().LatestMilestoneID
+
+	// the milestone is issued if the caller expects the latest milestone of the coordinator
+	_, err := coo.IssueMilestoneExpecting(randBlockIDs(t, 1), previousMilestoneID)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+
+	// a caller with an outdated view is rejected
+	_, err = coo.IssueMilestoneExpecting(randBlockIDs(t, 1), previousMilestoneID)
+	require.ErrorIs(t, err, coordinator.ErrPreviousMilestoneIDMismatch)
+	require.True(t, coordinator.IsCritical(err))
+	require.Contains(t, err.Error(), previousMilestoneID.ToHex())
+	require.EqualValues(t, 2, coo.State().LatestMilestoneIndex)
+}
+
+func TestQuorumHealthPoll(t *testing.T) {
+	var nodeDown atomic.Bool
+	var infoRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, nodeclient.RouteInfo, r.URL.Path)
+		infoRequests.Add(1)
+
+		if nodeDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+
+	coo, _ := newTestCoordinator(t, nil,
+		coordinator.WithQuorum(true, map[string][]*coordinator.QuorumClientConfig{
+			"group": {{BaseURL: server.URL}},
+		}, time.Second),
+		coordinator.WithQuorumHealthPoll(10*time.Millisecond),
+	)
+
+	waitForStats := func(condition func(stats coordinator.QuorumClientStatistic) bool) {
+		t.Helper()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for !condition(coo.QuorumStats()[0]) {
+			require.True(t, time.Now().Before(deadline), "quorum stats were not updated in time")
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// the nodes are polled without issuing a milestone
+	waitForStats(func(stats coordinator.QuorumClientStatistic) bool {
+		return infoRequests.Load() > 0 && stats.ResponseTimeSeconds > 0 && stats.Error == nil
+	})
+
+	// a node that went down is noticed between milestones
+	nodeDown.Store(true)
+	waitForStats(func(stats coordinator.QuorumClientStatistic) bool {
+		return stats.Error != nil
+	})
+
+	// the node is up again
+	nodeDown.Store(false)
+	waitForStats(func(stats coordinator.QuorumClientStatistic) bool {
+		return stats.Error == nil
+	})
+
+	// the polling stops at shutdown
+	require.NoError(t, coo.Shutdown(context.Background()))
+	time.Sleep(20 * time.Millisecond)
+	requestsAfterShutdown := infoRequests.Load()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, requestsAfterShutdown, infoRequests.Load())
+}
+
+func TestIssueKeepAliveMilestone(t *testing.T) {
+	coo, sender := newBootstrappedTestCoordinator(t, nil)
+
+	for i := 0; i < 2; i++ {
+		previousMilestoneBlockID := coo.State().LatestMilestoneBlockID
+
+		blockID, err := coo.IssueKeepAliveMilestone()
+		require.NoError(t, err)
+		require.Equal(t, coo.State().LatestMilestoneBlockID, blockID)
+
+		// the previous milestone is the only parent
+		sentBlocks := sender.sentBlocks()
+		milestoneBlock := sentBlocks[len(sentBlocks)-1]
+		require.Equal(t, iotago.BlockIDs{previousMilestoneBlockID}, milestoneBlock.Parents)
+
+		milestonePayload, ok := milestoneBlock.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		require.Equal(t, iotago.BlockIDs{previousMilestoneBlockID}, milestonePayload.Parents)
+	}
+	require.EqualValues(t, 3, coo.State().LatestMilestoneIndex)
+}
+
+func TestStatePersistFailed(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+
+	var failWrite atomic.Bool
+	coo, sender := newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithStateFilePath(stateFilePath),
+		coordinator.WithStateWriteRetry(3, time.Millisecond),
+		coordinator.WithStateTransitionObserver(func(_ coordinator.State, _ coordinator.State) {
+			if failWrite.Load() {
+				// a directory at the path of the state file lets every write fail
+				require.NoError(t, os.MkdirAll(stateFilePath, 0700))
+			}
+		}),
+	)
+	require.Equal(t, 3, coo.OptionsSnapshot().StateWriteRetryAttempts)
+
+	var persistedState *coordinator.State
+	var persistErr error
+	coo.Events.StatePersistFailed.Hook(events.NewClosure(func(state coordinator.State, err error) {
+		persistedState = &state
+		persistErr = err
+	}))
+
+	failWrite.Store(true)
+	_, err := coo.IssueMilestone(randBlockIDs(t, 1))
+	require.Error(t, err)
+	require.True(t, coordinator.IsCritical(err))
+
+	// the milestone was sent, so the event carries the state that references it
+	require.NotNil(t, persistedState)
+	require.Error(t, persistErr)
+	require.EqualValues(t, 2, persistedState.LatestMilestoneIndex)
+	require.Equal(t, *coo.State(), *persistedState)
+
+	sentBlocks := sender.sentBlocks()
+	require.Len(t, sentBlocks, 2)
+	sentBlockID, err := sentBlocks[1].ID()
+	require.NoError(t, err)
+	require.Equal(t, sentBlockID, persistedState.LatestMilestoneBlockID)
+}
+
+// sentMilestones returns the milestone payloads of the sent blocks by their index.
+func sentMilestones(t *testing.T, sender *testBlockSender) map[iotago.MilestoneIndex]*iotago.Milestone {
+	t.Helper()
+
+	milestones := make(map[iotago.MilestoneIndex]*iotago.Milestone)
+	for _, block := range sender.sentBlocks() {
+		if milestonePayload, ok := block.Payload.(*iotago.Milestone); ok {
+			milestones[milestonePayload.Index] = milestonePayload
+		}
+	}
+
+	return milestones
+}
+
+// fetchMilestoneFromMap returns a FetchMilestoneFunc that serves the given milestones.
+func fetchMilestoneFromMap(milestones map[iotago.MilestoneIndex]*iotago.Milestone) coordinator.FetchMilestoneFunc {
+	return func(index iotago.MilestoneIndex) (*iotago.Milestone, error) {
+		milestone, exists := milestones[index]
+		if !exists {
+			return nil, fmt.Errorf("milestone %d not found", index)
+		}
+
+		return milestone, nil
+	}
+}
+
+func TestVerifyChain(t *testing.T) {
+	signerProvider := testSignerProvider(t)
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithSignerProvider(signerProvider))
+	for i := 0; i < 3; i++ {
+		_, err := coo.IssueMilestone(randBlockIDs(t, 1))
+		require.NoError(t, err)
+	}
+
+	milestones := sentMilestones(t, sender)
+	require.Len(t, milestones, 4)
+
+	verifyChain := func(fromIndex iotago.MilestoneIndex, replacements map[iotago.MilestoneIndex]*iotago.Milestone) error {
+		chain := make(map[iotago.MilestoneIndex]*iotago.Milestone, len(milestones))
+		for index, milestone := range milestones {
+			chain[index] = milestone
+		}
+		for index, milestone := range replacements {
+			chain[index] = milestone
+		}
+
+		return coo.VerifyChain(context.Background(), fromIndex, fetchMilestoneFromMap(chain))
+	}
+
+	t.Run("valid chain", func(t *testing.T) {
+		require.NoError(t, verifyChain(0, nil))
+		require.NoError(t, verifyChain(3, nil))
+	})
+
+	t.Run("broken previous milestone ID link", func(t *testing.T) {
+		brokenLink := *milestones[3]
+		brokenLink.PreviousMilestoneID = iotago.MilestoneID{1}
+
+		err := verifyChain(1, map[iotago.MilestoneIndex]*iotago.Milestone{3: &brokenLink})
+		require.ErrorIs(t, err, coordinator.ErrMilestoneChainBroken)
+		require.ErrorContains(t, err, "milestone 3 does not reference the previous milestone")
+	})
+
+	t.Run("index mismatch", func(t *testing.T) {
+		err := verifyChain(1, map[iotago.MilestoneIndex]*iotago.Milestone{3: milestones[2]})
+		require.ErrorIs(t, err, coordinator.ErrMilestoneChainBroken)
+		require.ErrorContains(t, err, "milestone index mismatch, expected: 3, got: 2")
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		badSignature := *milestones[4]
+		signature, ok := milestones[4].Signatures[0].(*iotago.Ed25519Signature)
+		require.True(t, ok)
+
+		tamperedSignature := *signature
+		tamperedSignature.Signature[0] ^= 0xff
+		badSignature.Signatures = iotago.Signatures{&tamperedSignature}
+
+		err := verifyChain(1, map[iotago.MilestoneIndex]*iotago.Milestone{4: &badSignature})
+		require.ErrorIs(t, err, coordinator.ErrMilestoneChainBroken)
+		require.ErrorContains(t, err, "invalid signatures in milestone 4")
+	})
+
+	t.Run("latest milestone ID mismatch", func(t *testing.T) {
+		// a validly signed milestone with the latest index, which is not the latest milestone of the coordinator
+		otherCoo, otherSender := newBootstrappedTestCoordinator(t, nil, coordinator.WithSignerProvider(signerProvider))
+		for i := 0; i < 3; i++ {
+			_, err := otherCoo.IssueMilestone(randBlockIDs(t, 1))
+			require.NoError(t, err)
+		}
+
+		err := verifyChain(4, map[iotago.MilestoneIndex]*iotago.Milestone{4: sentMilestones(t, otherSender)[4]})
+		require.ErrorIs(t, err, coordinator.ErrMilestoneChainBroken)
+		require.ErrorContains(t, err, "latest milestone ID mismatch")
+	})
+
+	t.Run("missing milestone", func(t *testing.T) {
+		err := coo.VerifyChain(context.Background(), 1, func(index iotago.MilestoneIndex) (*iotago.Milestone, error) {
+			//nolint:nilnil // a fetch function that doesn't know the milestone
+			return nil, nil
+		})
+		require.ErrorContains(t, err, "failed to fetch milestone 1: milestone not found")
+	})
+
+	t.Run("state not initialized", func(t *testing.T) {
+		uninitializedCoo, _ := newTestCoordinator(t, nil)
+		err := uninitializedCoo.VerifyChain(context.Background(), 1, fetchMilestoneFromMap(milestones))
+		require.ErrorIs(t, err, coordinator.ErrStateNotInitialized)
+	})
+}
+
+func TestProbesDoNotWaitForIssuance(t *testing.T) {
+	sender := &testBlockSender{}
+	var blockSend atomic.Bool
+	sending := make(chan struct{})
+	releaseSend := make(chan struct{})
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if blockSend.Load() {
+			close(sending)
+			<-releaseSend
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	}
+
+	coo, _ := newBootstrappedTestCoordinator(t, sendBlock)
+	blockSend.Store(true)
+
+	issueDone := make(chan error, 1)
+	go func() {
+		_, err := coo.IssueMilestone(nil)
+		issueDone <- err
+	}()
+	<-sending
+
+	// the probes answer while the milestone is sent
+	readinessDone := make(chan []string, 1)
+	go func() {
+		_, reasons := coo.Readiness()
+		readinessDone <- reasons
+	}()
+
+	select {
+	case reasons := <-readinessDone:
+		require.Empty(t, reasons)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "readiness waited for the in-flight issuance")
+	}
+
+	healthDone := make(chan *coordinator.HealthStatus, 1)
+	go func() {
+		health, _ := coo.Health()
+		healthDone <- health
+	}()
+
+	select {
+	case health := <-healthDone:
+		require.NotNil(t, health)
+		require.True(t, health.Healthy)
+		require.EqualValues(t, 1, health.LatestMilestoneIndex)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "health waited for the in-flight issuance")
+	}
+
+	close(releaseSend)
+	require.NoError(t, <-issueDone)
+}
+
+func TestReadinessDuringIssuance(t *testing.T) {
+	coo, _ := newTestCoordinator(t, nil)
+
+	ready, reasons := coo.Readiness()
+	require.False(t, ready)
+	require.Equal(t, []string{"coordinator state not initialized"}, reasons)
+
+	// the published state is read without the milestone lock, which is checked by the race detector
+	issuanceDone := make(chan error, 1)
+	go func() {
+		if err := coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}); err != nil {
+			issuanceDone <- err
+
+			return
+		}
+
+		if _, err := coo.Bootstrap(); err != nil {
+			issuanceDone <- err
+
+			return
+		}
+
+		for i := 0; i < 10; i++ {
+			if _, err := coo.IssueMilestone(nil); err != nil {
+				issuanceDone <- err
+
+				return
+			}
+		}
+		issuanceDone <- nil
+	}()
+
+	for {
+		select {
+		case err := <-issuanceDone:
+			require.NoError(t, err)
+
+			ready, reasons = coo.Readiness()
+			require.True(t, ready, reasons)
+
+			return
+		default:
+			coo.Readiness()
+		}
+	}
+}
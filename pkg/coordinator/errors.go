@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+)
+
+// triggeredSoftError marks a soft error for which the coordinator already triggered the SoftError event.
+type triggeredSoftError struct {
+	err error
+}
+
+func (e *triggeredSoftError) Error() string {
+	return e.err.Error()
+}
+
+func (e *triggeredSoftError) Unwrap() error {
+	return e.err
+}
+
+// IsCritical returns whether the error, or any error it wraps, is a critical error.
+// The coordinator can't continue after a critical error, e.g. the state may be inconsistent with the network.
+func IsCritical(err error) bool {
+	return common.IsCriticalError(err) != nil
+}
+
+// IsSoft returns whether the error, or any error it wraps, is a soft error and not a critical one.
+// The operation that returned a soft error can be retried, e.g. the next milestone is issued as usual.
+func IsSoft(err error) bool {
+	return !IsCritical(err) && common.IsSoftError(err) != nil
+}
+
+// SoftErrorEventTriggered returns whether the coordinator already triggered the SoftError event for the error.
+// Callers that forward returned soft errors to the SoftError event should skip these errors.
+func SoftErrorEventTriggered(err error) bool {
+	var triggeredErr *triggeredSoftError
+
+	return errors.As(err, &triggeredErr)
+}
+
+// newTriggeredSoftError returns the error as a soft error, which is marked, so callers don't trigger the event for it again.
+// The event itself is triggered by triggerSoftErrorEvent, after the milestone lock was released.
+func newTriggeredSoftError(err error) error {
+	return common.SoftError(&triggeredSoftError{err: err})
+}
+
+// triggerSoftErrorEvent triggers the SoftError event for errors marked by newTriggeredSoftError.
+// The handlers are called synchronously, so this must not be called while the milestone lock is held,
+// otherwise handlers that call getters of the coordinator would deadlock.
+func (coo *Coordinator) triggerSoftErrorEvent(err error) {
+	var triggeredErr *triggeredSoftError
+	if errors.As(err, &triggeredErr) {
+		coo.Events.SoftError.Trigger(triggeredErr.err)
+	}
+}
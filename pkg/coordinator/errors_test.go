@@ -0,0 +1,50 @@
+package coordinator_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestErrorClassification(t *testing.T) {
+	plainErr := errors.New("plain error")
+
+	tests := []struct {
+		name     string
+		err      error
+		critical bool
+		soft     bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain", err: plainErr},
+		{name: "soft", err: common.SoftError(plainErr), soft: true},
+		{name: "critical", err: common.CriticalError(plainErr), critical: true},
+		{name: "wrapped soft", err: fmt.Errorf("failed: %w", common.SoftError(plainErr)), soft: true},
+		{name: "wrapped critical", err: fmt.Errorf("failed: %w", common.CriticalError(plainErr)), critical: true},
+		{name: "critical in soft", err: common.SoftError(common.CriticalError(plainErr)), critical: true},
+		{name: "soft in critical", err: common.CriticalError(common.SoftError(plainErr)), critical: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.critical, coordinator.IsCritical(test.err))
+			require.Equal(t, test.soft, coordinator.IsSoft(test.err))
+		})
+	}
+}
+
+func TestIssueMilestoneErrorClassification(t *testing.T) {
+	coo, _ := newBootstrappedTestCoordinator(t, nil)
+
+	// too many tips are a soft error, the next milestone can be issued with fewer tips
+	_, err := coo.IssueMilestone(randBlockIDs(t, iotago.BlockMaxParents))
+	require.ErrorIs(t, err, coordinator.ErrTooManyParents)
+	require.True(t, coordinator.IsSoft(err))
+	require.False(t, coordinator.IsCritical(err))
+}
@@ -10,14 +10,88 @@ func CheckpointCaller(handler interface{}, params ...interface{}) {
 	handler.(func(checkpointIndex int, tipIndex int, tipsTotal int, blockID iotago.BlockID))(params[0].(int), params[1].(int), params[2].(int), params[3].(iotago.BlockID))
 }
 
+// IssuedCheckpointBlockInfo holds the details of an issued checkpoint block.
+type IssuedCheckpointBlockInfo struct {
+	// the index of the checkpoint the block belongs to.
+	CheckpointIndex int
+	// the index of the block within the checkpoint.
+	BlockIndex int
+	// the total amount of blocks of the checkpoint.
+	BlocksTotal int
+	// the ID of the issued block.
+	BlockID iotago.BlockID
+	// the parents of the issued block, including the previous checkpoint block.
+	Parents iotago.BlockIDs
+}
+
+// CheckpointBlockDetailsCaller is used to signal issued checkpoint blocks including their parents.
+func CheckpointBlockDetailsCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(info *IssuedCheckpointBlockInfo))(params[0].(*IssuedCheckpointBlockInfo))
+}
+
 // MilestoneCaller is used to signal issued milestones.
 func MilestoneCaller(handler interface{}, params ...interface{}) {
 	//nolint:forcetypeassert // we will replace that with generic events anyway
 	handler.(func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, blockID iotago.BlockID))(params[0].(iotago.MilestoneIndex), params[1].(iotago.MilestoneID), params[2].(iotago.BlockID))
 }
 
+// IssuingMilestoneCaller is used to signal milestones that are about to be sent.
+func IssuingMilestoneCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, parents iotago.BlockIDs))(params[0].(iotago.MilestoneIndex), params[1].(iotago.MilestoneID), params[2].(iotago.BlockIDs))
+}
+
+// MilestoneSendFailedCaller is used to signal milestones that could not be sent.
+func MilestoneSendFailedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(index iotago.MilestoneIndex, err error))(params[0].(iotago.MilestoneIndex), params[1].(error))
+}
+
+// StatePersistFailedCaller is used to signal a coordinator state that could not be written to the state file.
+func StatePersistFailedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(state State, err error))(params[0].(State), params[1].(error))
+}
+
 // QuorumFinishedCaller is used to signal a finished quorum call.
 func QuorumFinishedCaller(handler interface{}, params ...interface{}) {
 	//nolint:forcetypeassert // we will replace that with generic events anyway
 	handler.(func(result *QuorumFinishedResult))(params[0].(*QuorumFinishedResult))
 }
+
+// LifecycleStateChangedCaller is used to signal a transition of the coordinator lifecycle.
+func LifecycleStateChangedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(change *LifecycleStateChange))(params[0].(*LifecycleStateChange))
+}
+
+// queueEvent queues the trigger of an event raised while the milestone lock is held.
+// The handlers are called synchronously, so the event is only triggered by triggerQueuedEvents
+// after the milestone lock was released, otherwise handlers that call methods of the coordinator would deadlock.
+func (coo *Coordinator) queueEvent(trigger func()) {
+	coo.queuedEventsLock.Lock()
+	defer coo.queuedEventsLock.Unlock()
+
+	coo.queuedEvents = append(coo.queuedEvents, trigger)
+}
+
+// triggerQueuedEvents triggers the queued events in the order they were raised.
+// It must not be called while the milestone lock is held.
+func (coo *Coordinator) triggerQueuedEvents() {
+	coo.queuedEventsLock.Lock()
+	queuedEvents := coo.queuedEvents
+	coo.queuedEvents = nil
+	coo.queuedEventsLock.Unlock()
+
+	for _, trigger := range queuedEvents {
+		trigger()
+	}
+}
+
+// publishState publishes a copy of the coordinator state, which is read by the getters without the milestone lock.
+// It must be called whenever the state changed, the caller must hold the milestone lock.
+func (coo *Coordinator) publishState() {
+	state := *coo.state
+	coo.publishedState.Store(&state)
+}
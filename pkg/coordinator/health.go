@@ -0,0 +1,66 @@
+package coordinator
+
+import (
+	"time"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// the amount of milestone intervals without a new milestone after which the coordinator is considered stalled.
+	stalledMilestoneIntervals = 3
+)
+
+// HealthStatus summarizes the health of the coordinator.
+type HealthStatus struct {
+	// whether the coordinator is healthy.
+	// A failing quorum doesn't make the coordinator unhealthy on its own, but it stalls the milestone issuance.
+	Healthy bool
+	// whether the node connected to the coordinator is synced.
+	NodeSynced bool
+	// whether the network was bootstrapped by the coordinator.
+	Bootstrapped bool
+	// whether the issuance of milestones and checkpoints is paused.
+	Paused bool
+	// whether the coordinator hit a critical error and rejects the issuance of milestones and checkpoints.
+	ReadOnly bool
+	// the index of the latest issued milestone.
+	LatestMilestoneIndex iotago.MilestoneIndex
+	// the time since the latest milestone was issued.
+	TimeSinceLatestMilestone time.Duration
+	// whether no milestone was issued for several milestone intervals.
+	Stalled bool
+	// the error of the last quorum check, nil if it succeeded or the quorum is disabled.
+	LastQuorumErr error
+}
+
+// Health returns a summary of the health of the coordinator, e.g. for liveness and readiness probes.
+// It uses the published state, so it doesn't wait for a milestone that is currently issued.
+// Returns an error if the coordinator state was not initialized yet.
+func (coo *Coordinator) Health() (*HealthStatus, error) {
+	state := coo.publishedState.Load()
+	if state == nil {
+		return nil, ErrStateNotInitialized
+	}
+
+	var lastQuorumErr error
+	if lastQuorumResult := coo.LastQuorumResult(); lastQuorumResult != nil {
+		lastQuorumErr = lastQuorumResult.Err
+	}
+
+	timeSinceLatestMilestone := coo.opts.clock.Now().Sub(state.LatestMilestoneTime)
+	health := &HealthStatus{
+		NodeSynced: coo.NodeSynced(),
+		// the pending bootstrap is cleared by the first milestone of the coordinator
+		Bootstrapped:             !state.PendingBootstrap,
+		Paused:                   coo.IsPaused(),
+		ReadOnly:                 coo.LifecycleState() == LifecycleStateReadOnly,
+		LatestMilestoneIndex:     state.LatestMilestoneIndex,
+		TimeSinceLatestMilestone: timeSinceLatestMilestone,
+		Stalled:                  timeSinceLatestMilestone > stalledMilestoneIntervals*coo.baseInterval(),
+		LastQuorumErr:            lastQuorumErr,
+	}
+	health.Healthy = health.NodeSynced && health.Bootstrapped && !health.Paused && !health.ReadOnly && !health.Stalled
+
+	return health, nil
+}
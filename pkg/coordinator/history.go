@@ -0,0 +1,177 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// MilestoneRecord holds the details of an issued milestone.
+// Only successfully issued milestones are recorded, so a record carries no outcome
+// and a compacted record is still known to be an issued milestone.
+type MilestoneRecord struct {
+	// the index of the milestone.
+	Index iotago.MilestoneIndex
+	// the ID of the milestone.
+	MilestoneID iotago.MilestoneID
+	// the ID of the block containing the milestone.
+	BlockID iotago.BlockID
+	// the timestamp of the milestone.
+	Timestamp time.Time
+	// the time it took to issue the milestone.
+	Duration time.Duration
+	// the amount of parents of the milestone.
+	ParentsCount int
+	// whether the milestone carried a receipt of the migrator service.
+	HasReceipt bool
+	// whether the record was compacted, only the index and the timestamp of a compacted record are kept.
+	// See WithHistoryCompactAge.
+	Compacted bool
+}
+
+// milestoneRecordDetails holds the details of a milestone record, which are dropped if the record is compacted.
+type milestoneRecordDetails struct {
+	milestoneID  iotago.MilestoneID
+	blockID      iotago.BlockID
+	duration     time.Duration
+	parentsCount int
+	hasReceipt   bool
+}
+
+// milestoneHistoryEntry is an entry of the milestone history.
+type milestoneHistoryEntry struct {
+	index     iotago.MilestoneIndex
+	timestamp time.Time
+	// the details of the milestone, nil if the entry was compacted.
+	details *milestoneRecordDetails
+}
+
+// record returns the MilestoneRecord of the entry.
+func (e milestoneHistoryEntry) record() MilestoneRecord {
+	if e.details == nil {
+		return MilestoneRecord{
+			Index:     e.index,
+			Timestamp: e.timestamp,
+			Compacted: true,
+		}
+	}
+
+	return MilestoneRecord{
+		Index:        e.index,
+		MilestoneID:  e.details.milestoneID,
+		BlockID:      e.details.blockID,
+		Timestamp:    e.timestamp,
+		Duration:     e.details.duration,
+		ParentsCount: e.details.parentsCount,
+		HasReceipt:   e.details.hasReceipt,
+	}
+}
+
+// milestoneHistory is a ring buffer of the latest issued milestones.
+// Entries older than compactAge only keep the index and the timestamp of the milestone to save memory.
+// The entries are compacted whenever a milestone is added or the history is read,
+// so a coordinator that stops issuing milestones still compacts its history on the next read.
+type milestoneHistory struct {
+	syncutils.RWMutex
+
+	// the recorded milestones, the oldest entry is overwritten if the buffer is full.
+	entries []milestoneHistoryEntry
+	// the position of the next entry.
+	next int
+	// whether the buffer is full.
+	full bool
+	// the age after which entries are compacted (0 = disabled).
+	compactAge time.Duration
+	// used to determine the age of the entries.
+	clock Clock
+}
+
+// newMilestoneHistory creates a history of the latest size issued milestones.
+func newMilestoneHistory(size int, compactAge time.Duration, clock Clock) *milestoneHistory {
+	return &milestoneHistory{
+		entries:    make([]milestoneHistoryEntry, size),
+		compactAge: compactAge,
+		clock:      clock,
+	}
+}
+
+// add records an issued milestone and compacts the entries older than compactAge.
+func (h *milestoneHistory) add(record MilestoneRecord) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.entries[h.next] = milestoneHistoryEntry{
+		index:     record.Index,
+		timestamp: record.Timestamp,
+		details: &milestoneRecordDetails{
+			milestoneID:  record.MilestoneID,
+			blockID:      record.BlockID,
+			duration:     record.Duration,
+			parentsCount: record.ParentsCount,
+			hasReceipt:   record.HasReceipt,
+		},
+	}
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+
+	h.compact()
+}
+
+// ordered returns the position of the oldest entry and the amount of entries.
+// The caller must hold the lock.
+func (h *milestoneHistory) ordered() (int, int) {
+	if !h.full {
+		return 0, h.next
+	}
+
+	return h.next, len(h.entries)
+}
+
+// compact drops the details of the entries older than compactAge.
+// The entries are ordered by their timestamp, so the compaction stops at the first entry that is young enough.
+// The caller must hold the lock.
+func (h *milestoneHistory) compact() {
+	if h.compactAge <= 0 {
+		return
+	}
+
+	now := h.clock.Now()
+	oldest, count := h.ordered()
+	for i := 0; i < count; i++ {
+		entry := &h.entries[(oldest+i)%len(h.entries)]
+		if now.Sub(entry.timestamp) <= h.compactAge {
+			return
+		}
+		entry.details = nil
+	}
+}
+
+// list compacts the entries older than compactAge and returns the recorded milestones,
+// ordered from the oldest to the latest.
+func (h *milestoneHistory) list() []MilestoneRecord {
+	h.Lock()
+	defer h.Unlock()
+
+	h.compact()
+
+	oldest, count := h.ordered()
+	records := make([]MilestoneRecord, 0, count)
+	for i := 0; i < count; i++ {
+		records = append(records, h.entries[(oldest+i)%len(h.entries)].record())
+	}
+
+	return records
+}
+
+// History returns the latest issued milestones, ordered from the oldest to the latest.
+// Returns nil if the history is disabled, see WithHistorySize.
+func (coo *Coordinator) History() []MilestoneRecord {
+	if coo.history == nil {
+		return nil
+	}
+
+	return coo.history.list()
+}
@@ -0,0 +1,83 @@
+package coordinator
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// the amount of recent back pressure checks of milestones the adaptive interval is computed from.
+	backPressureHistorySize = 10
+)
+
+// backPressureHistory holds the recent results of the back pressure checks of milestones.
+type backPressureHistory struct {
+	// the results of the checks, true if congestion was signaled.
+	congested [backPressureHistorySize]bool
+	// the position of the next result.
+	next int
+	// the amount of results in the history.
+	count int
+}
+
+// add adds the result of a back pressure check and overwrites the oldest one if the history is full.
+func (h *backPressureHistory) add(congested bool) {
+	h.congested[h.next] = congested
+	h.next = (h.next + 1) % backPressureHistorySize
+
+	if h.count < backPressureHistorySize {
+		h.count++
+	}
+}
+
+// congestedCount returns the amount of checks in the history that signaled congestion.
+func (h *backPressureHistory) congestedCount() int {
+	congestedCount := 0
+	for i := 0; i < h.count; i++ {
+		if h.congested[i] {
+			congestedCount++
+		}
+	}
+
+	return congestedCount
+}
+
+// adaptInterval adds the result of a back pressure check of a milestone to the history
+// and recomputes the adaptive interval. The interval grows linearly with the share
+// of congested checks from the minimum up to the maximum interval.
+// It does nothing if the interval is not adaptive.
+func (coo *Coordinator) adaptInterval(congested bool) {
+	coo.milestoneIntervalLock.Lock()
+	defer coo.milestoneIntervalLock.Unlock()
+
+	if coo.backPressureHistory == nil {
+		return
+	}
+
+	coo.backPressureHistory.add(congested)
+
+	minInterval, maxInterval := coo.opts.adaptiveIntervalMin, coo.opts.adaptiveIntervalMax
+	interval := minInterval + (maxInterval-minInterval)*time.Duration(coo.backPressureHistory.congestedCount())/time.Duration(coo.backPressureHistory.count)
+
+	if interval != coo.milestoneInterval {
+		coo.LogInfof("adaptive milestone interval changed from %v to %v", coo.milestoneInterval, interval)
+		coo.milestoneInterval = interval
+	}
+}
+
+// jitterInterval returns the interval plus or minus a random jitter of up to the configured fraction of the interval.
+// The jitter is always smaller than the interval, so the result is positive.
+func (coo *Coordinator) jitterInterval(interval time.Duration) time.Duration {
+	maxJitter := int64(float64(interval) * coo.opts.intervalJitter)
+	if maxJitter >= int64(interval) {
+		// rounding must not make the interval non-positive
+		maxJitter = int64(interval) - 1
+	}
+
+	if maxJitter <= 0 {
+		return interval
+	}
+
+	//nolint:gosec // the jitter doesn't need to be cryptographically secure
+	return interval + time.Duration(rand.Int63n(2*maxJitter+1)-maxJitter)
+}
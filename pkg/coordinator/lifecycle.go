@@ -0,0 +1,151 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LifecycleState is the state of the coordinator lifecycle.
+type LifecycleState int
+
+const (
+	// LifecycleStateInitializing is the state before the coordinator state was initialized.
+	LifecycleStateInitializing LifecycleState = iota
+	// LifecycleStateBootstrapping is the state until the first milestone of a new network was issued.
+	LifecycleStateBootstrapping
+	// LifecycleStateRunning is the state while the coordinator issues milestones.
+	LifecycleStateRunning
+	// LifecycleStatePaused is the state while the milestone issuance is paused.
+	LifecycleStatePaused
+	// LifecycleStateDegraded is the state after the last milestone issuance failed with a soft error.
+	LifecycleStateDegraded
+	// LifecycleStateReadOnly is the state after the coordinator hit a critical error and must not issue milestones anymore.
+	// It is only left by calling ClearReadOnly or Shutdown.
+	LifecycleStateReadOnly
+	// LifecycleStateShuttingDown is the state after the coordinator was shut down.
+	LifecycleStateShuttingDown
+)
+
+// String returns the name of the LifecycleState.
+func (s LifecycleState) String() string {
+	switch s {
+	case LifecycleStateInitializing:
+		return "initializing"
+	case LifecycleStateBootstrapping:
+		return "bootstrapping"
+	case LifecycleStateRunning:
+		return "running"
+	case LifecycleStatePaused:
+		return "paused"
+	case LifecycleStateDegraded:
+		return "degraded"
+	case LifecycleStateReadOnly:
+		return "read-only"
+	case LifecycleStateShuttingDown:
+		return "shutting-down"
+	default:
+		return "unknown"
+	}
+}
+
+// LifecycleStateChange holds the information about a transition of the coordinator lifecycle.
+type LifecycleStateChange struct {
+	// the state before the transition.
+	Previous LifecycleState
+	// the state after the transition.
+	Current LifecycleState
+	// the time of the transition.
+	Timestamp time.Time
+}
+
+// LifecycleStateChangedFunc is called on every transition of the coordinator lifecycle.
+type LifecycleStateChangedFunc = func(change *LifecycleStateChange)
+
+// LifecycleState returns the current state of the coordinator lifecycle.
+func (coo *Coordinator) LifecycleState() LifecycleState {
+	coo.lifecycleLock.RLock()
+	defer coo.lifecycleLock.RUnlock()
+
+	return coo.lifecycleState
+}
+
+// ClearReadOnly allows the issuance of milestones and checkpoints again after the cause of the critical error,
+// which made the coordinator read-only, was resolved.
+// It has no effect if the coordinator is not read-only.
+func (coo *Coordinator) ClearReadOnly() {
+	state := LifecycleStateRunning
+	if coo.IsPaused() {
+		state = LifecycleStatePaused
+	}
+
+	if change := coo.transitionLifecycleState(state, true); change != nil {
+		coo.triggerLifecycleStateChanged(change)
+	}
+}
+
+// setLifecycleState transitions the coordinator lifecycle to the given state.
+// A read-only coordinator is only transitioned if it is shut down.
+// It must not be called while the milestone lock is held, see setLifecycleStateLocked.
+func (coo *Coordinator) setLifecycleState(state LifecycleState) {
+	if change := coo.transitionLifecycleState(state, false); change != nil {
+		coo.triggerLifecycleStateChanged(change)
+	}
+}
+
+// setLifecycleStateLocked transitions the coordinator lifecycle to the given state like setLifecycleState.
+// The caller must hold the milestone lock, the LifecycleStateChanged event is triggered after it was released.
+func (coo *Coordinator) setLifecycleStateLocked(state LifecycleState) {
+	if change := coo.transitionLifecycleState(state, false); change != nil {
+		coo.queueEvent(func() { coo.triggerLifecycleStateChanged(change) })
+	}
+}
+
+// transitionLifecycleState transitions the coordinator lifecycle to the given state.
+// If clearReadOnly is set, only a read-only coordinator is transitioned, otherwise a read-only coordinator
+// is only transitioned if it is shut down.
+// Returns the transition, or nil if the state didn't change.
+func (coo *Coordinator) transitionLifecycleState(state LifecycleState, clearReadOnly bool) *LifecycleStateChange {
+	coo.lifecycleLock.Lock()
+	readOnly := coo.lifecycleState == LifecycleStateReadOnly
+	if coo.lifecycleState == state || (readOnly != clearReadOnly && state != LifecycleStateShuttingDown) {
+		coo.lifecycleLock.Unlock()
+
+		return nil
+	}
+
+	change := &LifecycleStateChange{
+		Previous:  coo.lifecycleState,
+		Current:   state,
+		Timestamp: coo.opts.clock.Now(),
+	}
+	coo.lifecycleState = state
+	coo.lifecycleLock.Unlock()
+
+	coo.LogInfof("coordinator lifecycle state changed from %s to %s", change.Previous, change.Current)
+
+	return change
+}
+
+// triggerLifecycleStateChanged fires the LifecycleStateChanged event and the optional callback.
+// It must not be called while the milestone lock is held.
+func (coo *Coordinator) triggerLifecycleStateChanged(change *LifecycleStateChange) {
+	coo.Events.LifecycleStateChanged.Trigger(change)
+	if coo.opts.lifecycleStateChangedFunc != nil {
+		coo.opts.lifecycleStateChangedFunc(change)
+	}
+}
+
+// updateLifecycleStateAfterIssuance transitions the coordinator lifecycle depending on the result of a milestone issuance.
+func (coo *Coordinator) updateLifecycleStateAfterIssuance(err error) {
+	switch {
+	case errors.Is(err, ErrCoordinatorPaused), errors.Is(err, ErrCoordinatorShutdown), errors.Is(err, ErrCoordinatorReadOnly):
+		// the coordinator stays paused, shut down or read-only
+	case err == nil:
+		coo.setLifecycleState(LifecycleStateRunning)
+	case IsCritical(err):
+		coo.setLifecycleState(LifecycleStateReadOnly)
+	default:
+		coo.setLifecycleState(LifecycleStateDegraded)
+	}
+}
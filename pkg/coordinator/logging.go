@@ -0,0 +1,46 @@
+package coordinator
+
+import (
+	"time"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// the field names of the structured log messages, they must be stable for log aggregation.
+const (
+	logFieldMilestoneIndex     = "ms_index"
+	logFieldMilestoneID        = "ms_id"
+	logFieldNodeMilestoneIndex = "node_ms_index"
+	logFieldBlockID            = "block_id"
+	logFieldDurationMs         = "duration_ms"
+	logFieldGroup              = "group"
+	logFieldBaseURL            = "base_url"
+	logFieldCorrelationID      = "correlation_id"
+	logFieldBackPressure       = "back_pressure"
+	logFieldBlockType          = "block_type"
+	logFieldError              = "err"
+)
+
+// logInfow logs a message with the given key/value pairs as structured fields.
+func (coo *Coordinator) logInfow(msg string, keysAndValues ...interface{}) {
+	if logger := coo.Logger(); logger != nil {
+		logger.Infow(msg, keysAndValues...)
+	}
+}
+
+// logWarnw logs a warning with the given key/value pairs as structured fields.
+func (coo *Coordinator) logWarnw(msg string, keysAndValues ...interface{}) {
+	if logger := coo.Logger(); logger != nil {
+		logger.Warnw(msg, keysAndValues...)
+	}
+}
+
+// milestoneIDField returns the hex encoded milestone ID for the structured log messages.
+func milestoneIDField(milestoneID iotago.MilestoneID) string {
+	return iotago.EncodeHex(milestoneID[:])
+}
+
+// durationMsField returns the duration in milliseconds for the structured log messages.
+func durationMsField(duration time.Duration) int64 {
+	return duration.Milliseconds()
+}
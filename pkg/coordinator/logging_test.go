@@ -0,0 +1,40 @@
+package coordinator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestStructuredLogging(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithLogger(zap.New(core).Sugar()))
+
+	issuedLogs := logs.FilterMessage("milestone issued").All()
+	require.Len(t, issuedLogs, 1)
+
+	fields := issuedLogs[0].ContextMap()
+	require.EqualValues(t, 1, fields["ms_index"])
+	require.Equal(t, iotago.EncodeHex(coo.State().LatestMilestoneID[:]), fields["ms_id"])
+	require.Equal(t, coo.State().LatestMilestoneBlockID.ToHex(), fields["block_id"])
+	require.Contains(t, fields, "duration_ms")
+
+	coo.AddNamedBackPressureFunc("test", func() bool { return true })
+	_, err := coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrNodeLoadTooHigh)
+
+	holdLogs := logs.FilterMessage("holding issuance, back pressure signaled").All()
+	require.Len(t, holdLogs, 1)
+	require.Equal(t, "test", holdLogs[0].ContextMap()["back_pressure"])
+
+	softErrorLogs := logs.FilterMessage("milestone issuance failed with a soft error").All()
+	require.Len(t, softErrorLogs, 1)
+	require.EqualValues(t, 2, softErrorLogs[0].ContextMap()["ms_index"])
+}
@@ -0,0 +1,99 @@
+package coordinator
+
+import (
+	"time"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// IssuancePhase is a phase of the milestone issuance.
+type IssuancePhase string
+
+const (
+	// IssuancePhaseMerkle is the computation of the merkle roots of the white-flag mutations.
+	IssuancePhaseMerkle IssuancePhase = "merkle"
+	// IssuancePhaseQuorum is the check of the merkle roots by the quorum.
+	IssuancePhaseQuorum IssuancePhase = "quorum"
+	// IssuancePhaseSigning is the creation and the signing of the milestone.
+	IssuancePhaseSigning IssuancePhase = "signing"
+	// IssuancePhaseSend is the sending of the milestone block to the network.
+	IssuancePhaseSend IssuancePhase = "send"
+	// IssuancePhaseStateWrite is the writing of the coordinator state file.
+	IssuancePhaseStateWrite IssuancePhase = "state_write"
+)
+
+// IssuanceTiming holds the durations of the phases of a milestone issuance.
+// The duration of a phase is 0 if the phase was skipped or didn't complete.
+type IssuanceTiming struct {
+	// the index of the issued milestone.
+	MilestoneIndex iotago.MilestoneIndex
+	// the duration of the computation of the merkle roots.
+	Merkle time.Duration
+	// the duration of the check of the merkle roots by the quorum.
+	Quorum time.Duration
+	// the duration of the creation and the signing of the milestone.
+	Signing time.Duration
+	// the duration of sending the milestone block to the network.
+	Send time.Duration
+	// the duration of writing the coordinator state file.
+	Persist time.Duration
+	// the total duration of the issuance.
+	Total time.Duration
+	// the error of the issuance, nil if it succeeded.
+	Err error
+}
+
+// setPhaseDuration sets the duration of the given phase.
+func (t *IssuanceTiming) setPhaseDuration(phase IssuancePhase, duration time.Duration) {
+	switch phase {
+	case IssuancePhaseMerkle:
+		t.Merkle = duration
+	case IssuancePhaseQuorum:
+		t.Quorum = duration
+	case IssuancePhaseSigning:
+		t.Signing = duration
+	case IssuancePhaseSend:
+		t.Send = duration
+	case IssuancePhaseStateWrite:
+		t.Persist = duration
+	}
+}
+
+// observePhaseDuration records the duration of a completed phase of the milestone issuance in the metrics and the timing.
+func (coo *Coordinator) observePhaseDuration(timing *IssuanceTiming, phase IssuancePhase, duration time.Duration) {
+	coo.opts.metrics.ObservePhaseDuration(phase, duration)
+	timing.setPhaseDuration(phase, duration)
+}
+
+// Metrics records metrics of the milestone issuance.
+type Metrics interface {
+	// ObserveIssuanceDuration is called with the total duration of every successfully issued milestone.
+	ObserveIssuanceDuration(duration time.Duration)
+	// ObservePhaseDuration is called with the duration of every completed phase of the milestone issuance.
+	ObservePhaseDuration(phase IssuancePhase, duration time.Duration)
+	// IncIssuanceErrors is called for every error of the milestone issuance.
+	IncIssuanceErrors(critical bool)
+}
+
+// noopMetrics is used if no Metrics were configured.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveIssuanceDuration(_ time.Duration) {}
+
+func (noopMetrics) ObservePhaseDuration(_ IssuancePhase, _ time.Duration) {}
+
+func (noopMetrics) IncIssuanceErrors(_ bool) {}
+
+// observeIssuanceError records the error of a milestone issuance if it is a soft or critical error.
+func (coo *Coordinator) observeIssuanceError(err error) {
+	switch {
+	case IsCritical(err):
+		coo.opts.metrics.IncIssuanceErrors(true)
+	case IsSoft(err):
+		coo.opts.metrics.IncIssuanceErrors(false)
+	default:
+		return
+	}
+
+	coo.setLastIssuanceError(err)
+}
@@ -0,0 +1,81 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusMetrics records the metrics of the milestone issuance as Prometheus metrics.
+// It is a prometheus.Collector and has to be registered at a prometheus.Registerer.
+type PrometheusMetrics struct {
+	issuanceDuration      prometheus.Histogram
+	issuancePhaseDuration *prometheus.HistogramVec
+	issuanceErrors        *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates new PrometheusMetrics.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		issuanceDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: "iota",
+				Subsystem: "coordinator",
+				Name:      "milestone_issuance_duration",
+				Help:      "Durations of the milestone issuance. [s]",
+				Buckets:   prometheus.DefBuckets,
+			}),
+		issuancePhaseDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "iota",
+				Subsystem: "coordinator",
+				Name:      "milestone_issuance_phase_duration",
+				Help:      "Durations of the phases of the milestone issuance. [s]",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"phase"},
+		),
+		issuanceErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "iota",
+				Subsystem: "coordinator",
+				Name:      "milestone_issuance_error_count",
+				Help:      "Number of encountered errors of the milestone issuance by type.",
+			},
+			[]string{"type"},
+		),
+	}
+}
+
+// ObserveIssuanceDuration records the total duration of a milestone issuance.
+func (m *PrometheusMetrics) ObserveIssuanceDuration(duration time.Duration) {
+	m.issuanceDuration.Observe(duration.Seconds())
+}
+
+// ObservePhaseDuration records the duration of a phase of the milestone issuance.
+func (m *PrometheusMetrics) ObservePhaseDuration(phase IssuancePhase, duration time.Duration) {
+	m.issuancePhaseDuration.WithLabelValues(string(phase)).Observe(duration.Seconds())
+}
+
+// IncIssuanceErrors counts an error of the milestone issuance.
+func (m *PrometheusMetrics) IncIssuanceErrors(critical bool) {
+	errorType := "soft"
+	if critical {
+		errorType = "critical"
+	}
+	m.issuanceErrors.WithLabelValues(errorType).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (m *PrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.issuanceDuration.Describe(ch)
+	m.issuancePhaseDuration.Describe(ch)
+	m.issuanceErrors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *PrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.issuanceDuration.Collect(ch)
+	m.issuancePhaseDuration.Collect(ch)
+	m.issuanceErrors.Collect(ch)
+}
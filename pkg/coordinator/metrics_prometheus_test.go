@@ -0,0 +1,46 @@
+package coordinator_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := coordinator.NewPrometheusMetrics()
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(metrics))
+
+	coo, _ := newBootstrappedTestCoordinator(t, nil, coordinator.WithMetrics(metrics))
+
+	_, err := coo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	// the quorum is disabled
+	require.Equal(t, 4, testutil.CollectAndCount(metrics, "iota_coordinator_milestone_issuance_phase_duration"))
+	require.Equal(t, 1, testutil.CollectAndCount(metrics, "iota_coordinator_milestone_issuance_duration"))
+	require.Equal(t, 0, testutil.CollectAndCount(metrics, "iota_coordinator_milestone_issuance_error_count"))
+
+	coo.Pause()
+	_, err = coo.IssueMilestone(nil)
+	require.ErrorIs(t, err, coordinator.ErrCoordinatorPaused)
+
+	metricFamilies, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, metricFamily := range metricFamilies {
+		switch metricFamily.GetName() {
+		case "iota_coordinator_milestone_issuance_duration":
+			require.EqualValues(t, 2, metricFamily.GetMetric()[0].GetHistogram().GetSampleCount())
+		case "iota_coordinator_milestone_issuance_error_count":
+			require.Len(t, metricFamily.GetMetric(), 1)
+			require.Equal(t, "soft", metricFamily.GetMetric()[0].GetLabel()[0].GetValue())
+			require.EqualValues(t, 1, metricFamily.GetMetric()[0].GetCounter().GetValue())
+		}
+	}
+}
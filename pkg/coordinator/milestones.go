@@ -1,6 +1,11 @@
 package coordinator
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/iotaledger/hive.go/serializer/v2"
@@ -9,30 +14,104 @@ import (
 )
 
 // createCheckpoint creates a checkpoint block.
-func (coo *Coordinator) createCheckpoint(parents iotago.BlockIDs) (*iotago.Block, error) {
-
-	protoParams := coo.protoParamsFunc()
-
-	iotaBlock, err := builder.
-		NewBlockBuilder().
-		ProtocolVersion(protoParams.Version).
-		Parents(parents).
-		Build()
-	if err != nil {
-		return nil, err
+// The parents must already be sorted lexically and free of duplicates, they are only validated here.
+func (coo *Coordinator) createCheckpoint(protoParams *iotago.ProtocolParameters, parents iotago.BlockIDs) (*iotago.Block, error) {
+	iotaBlock := &iotago.Block{
+		ProtocolVersion: protoParams.Version,
+		Parents:         parents,
 	}
 
 	// Validate
-	_, err = iotaBlock.Serialize(serializer.DeSeriModePerformValidation, protoParams)
-	if err != nil {
+	if _, err := iotaBlock.Serialize(serializer.DeSeriModePerformValidation, protoParams); err != nil {
 		return nil, err
 	}
 
 	return iotaBlock, nil
 }
 
+// lexicalBlockIDs sorts block IDs lexically without the reflection overhead of sort.Slice.
+type lexicalBlockIDs iotago.BlockIDs
+
+func (ids lexicalBlockIDs) Len() int { return len(ids) }
+
+func (ids lexicalBlockIDs) Less(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 }
+
+func (ids lexicalBlockIDs) Swap(i, j int) { ids[i], ids[j] = ids[j], ids[i] }
+
+// sortedUniqueBlockIDs returns a lexically sorted copy of the block IDs without duplicates.
+func sortedUniqueBlockIDs(blockIDs iotago.BlockIDs) iotago.BlockIDs {
+	sorted := append(make(iotago.BlockIDs, 0, len(blockIDs)), blockIDs...)
+	sort.Sort(lexicalBlockIDs(sorted))
+
+	unique := sorted[:0]
+	for i, blockID := range sorted {
+		if i == 0 || blockID != sorted[i-1] {
+			unique = append(unique, blockID)
+		}
+	}
+
+	return unique
+}
+
+// checkpointParents returns the parents of a checkpoint block, the sorted tips plus the previous checkpoint block.
+// The previous checkpoint block is inserted at its lexical position, so the result doesn't need to be sorted again.
+func checkpointParents(lastCheckpointBlockID iotago.BlockID, sortedTips iotago.BlockIDs) iotago.BlockIDs {
+	pos := sort.Search(len(sortedTips), func(i int) bool {
+		return bytes.Compare(sortedTips[i][:], lastCheckpointBlockID[:]) >= 0
+	})
+
+	if pos < len(sortedTips) && sortedTips[pos] == lastCheckpointBlockID {
+		// the previous checkpoint block is one of the tips
+		return append(make(iotago.BlockIDs, 0, len(sortedTips)), sortedTips...)
+	}
+
+	parents := make(iotago.BlockIDs, 0, len(sortedTips)+1)
+	parents = append(parents, sortedTips[:pos]...)
+	parents = append(parents, lastCheckpointBlockID)
+
+	return append(parents, sortedTips[pos:]...)
+}
+
+// verifyMilestoneParents checks that the parents used for the white flag computation are sorted lexically without duplicates,
+// and that the milestone and its block reference byte-identical parents.
+// Otherwise the nodes would compute different merkle roots for the milestone.
+func verifyMilestoneParents(whiteFlagParents iotago.BlockIDs, milestoneBlock *iotago.Block) error {
+	for i := 1; i < len(whiteFlagParents); i++ {
+		if bytes.Compare(whiteFlagParents[i-1][:], whiteFlagParents[i][:]) >= 0 {
+			return fmt.Errorf("%w: white flag parents are not sorted lexically without duplicates at position %d", ErrMilestoneParentsMismatch, i)
+		}
+	}
+
+	milestonePayload, ok := milestoneBlock.Payload.(*iotago.Milestone)
+	if !ok {
+		return fmt.Errorf("%w: block doesn't contain a milestone", ErrMilestoneParentsMismatch)
+	}
+
+	for _, referenced := range []struct {
+		name    string
+		parents iotago.BlockIDs
+	}{
+		{name: "milestone", parents: milestonePayload.Parents},
+		{name: "block", parents: milestoneBlock.Parents},
+	} {
+		name, parents := referenced.name, referenced.parents
+		if len(parents) != len(whiteFlagParents) {
+			return fmt.Errorf("%w: %s has %d parents, white flag used %d", ErrMilestoneParentsMismatch, name, len(parents), len(whiteFlagParents))
+		}
+
+		for i := range parents {
+			if parents[i] != whiteFlagParents[i] {
+				return fmt.Errorf("%w: %s parent %d is %s, white flag used %s", ErrMilestoneParentsMismatch, name, i, parents[i].ToHex(), whiteFlagParents[i].ToHex())
+			}
+		}
+	}
+
+	return nil
+}
+
 // createMilestone creates a signed milestone block.
-func (coo *Coordinator) createMilestone(index iotago.MilestoneIndex, timestamp uint32, parents iotago.BlockIDs, receipt *iotago.ReceiptMilestoneOpt, previousMilestoneID iotago.MilestoneID, merkleProof *MilestoneMerkleRoots) (*iotago.Block, error) {
+// Signing retries are aborted if the context is done or the coordinator is shut down.
+func (coo *Coordinator) createMilestone(ctx context.Context, index iotago.MilestoneIndex, timestamp uint32, parents iotago.BlockIDs, receipt *iotago.ReceiptMilestoneOpt, previousMilestoneID iotago.MilestoneID, merkleProof *MilestoneMerkleRoots) (*iotago.Block, error) {
 	milestoneIndexSigner := coo.signerProvider.MilestoneIndexSigner(index)
 	pubKeys := milestoneIndexSigner.PublicKeys()
 
@@ -49,6 +128,20 @@ func (coo *Coordinator) createMilestone(index iotago.MilestoneIndex, timestamp u
 		msPayload.Opts = iotago.MilestoneOpts{receipt}
 	}
 
+	if coo.pendingProtocolParametersOpt != nil && coo.pendingProtocolParametersOpt.TargetMilestoneIndex == index {
+		// the options are ordered by their type, so the protocol parameters follow the receipt
+		msPayload.Opts = append(msPayload.Opts, coo.pendingProtocolParametersOpt.Clone())
+	}
+
+	if coo.opts.milestoneMetadataFunc != nil {
+		if metadata := coo.opts.milestoneMetadataFunc(index); len(metadata) > 0 {
+			if len(metadata) > iotago.MaxMetadataLength {
+				return nil, fmt.Errorf("%w: %d bytes, maximum: %d bytes", ErrMilestoneMetadataTooLong, len(metadata), iotago.MaxMetadataLength)
+			}
+			msPayload.Metadata = metadata
+		}
+	}
+
 	iotaBlock, err := builder.
 		NewBlockBuilder().
 		ProtocolVersion(protoParams.Version).
@@ -59,7 +152,8 @@ func (coo *Coordinator) createMilestone(index iotago.MilestoneIndex, timestamp u
 		return nil, err
 	}
 
-	if err := msPayload.Sign(pubKeys, coo.createSigningFuncWithRetries(milestoneIndexSigner.SigningFunc())); err != nil {
+	signingFunc := parallelSigningFunc(milestoneIndexSigner.SigningFunc(), coo.opts.signingParallelism)
+	if err := msPayload.Sign(pubKeys, coo.createSigningFuncWithRetries(ctx, signingFunc)); err != nil {
 		return nil, err
 	}
 
@@ -76,7 +170,8 @@ func (coo *Coordinator) createMilestone(index iotago.MilestoneIndex, timestamp u
 }
 
 // wraps the given MilestoneSigningFunc into a with retries enhanced version.
-func (coo *Coordinator) createSigningFuncWithRetries(signingFunc iotago.MilestoneSigningFunc) iotago.MilestoneSigningFunc {
+// The retries are aborted with the context error if the context is done or the coordinator is shut down.
+func (coo *Coordinator) createSigningFuncWithRetries(ctx context.Context, signingFunc iotago.MilestoneSigningFunc) iotago.MilestoneSigningFunc {
 	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) (sigs []iotago.MilestoneSignature, err error) {
 		if coo.opts.signingRetryAmount <= 0 {
 			return signingFunc(pubKeys, msEssence)
@@ -85,8 +180,21 @@ func (coo *Coordinator) createSigningFuncWithRetries(signingFunc iotago.Mileston
 			sigs, err = signingFunc(pubKeys, msEssence)
 			if err != nil {
 				if i+1 != coo.opts.signingRetryAmount {
-					coo.LogWarnf("signing attempt failed: %s, retrying in %v, retries left %d", err, coo.opts.signingRetryTimeout, coo.opts.signingRetryAmount-(i+1))
-					time.Sleep(coo.opts.signingRetryTimeout)
+					backoff := coo.signingRetryBackoff(i)
+					coo.LogWarnf("signing attempt failed: %s, retrying in %v, retries left %d", err, backoff, coo.opts.signingRetryAmount-(i+1))
+
+					timer := time.NewTimer(backoff)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+
+						return nil, fmt.Errorf("signing aborted after %d attempts: %w", i+1, ctx.Err())
+					case <-coo.shutdownCtx.Done():
+						timer.Stop()
+
+						return nil, fmt.Errorf("signing aborted after %d attempts: %w", i+1, coo.shutdownCtx.Err())
+					case <-timer.C:
+					}
 				}
 
 				continue
@@ -99,3 +207,61 @@ func (coo *Coordinator) createSigningFuncWithRetries(signingFunc iotago.Mileston
 		return
 	}
 }
+
+// signingRetryBackoff returns the time to wait after the given failed signing attempt (starting at 0).
+// Without a maximum backoff the signing retry timeout is used for every attempt.
+// Otherwise the backoff grows exponentially from the signing retry timeout up to the maximum,
+// with a random jitter to not retry synchronized with other signers.
+func (coo *Coordinator) signingRetryBackoff(attempt int) time.Duration {
+	if coo.opts.signingMaxBackoff <= 0 {
+		return coo.opts.signingRetryTimeout
+	}
+
+	backoff := coo.opts.signingMaxBackoff
+	if attempt < 32 {
+		if exponentialBackoff := coo.opts.signingRetryTimeout << attempt; exponentialBackoff >= 0 && exponentialBackoff < backoff {
+			backoff = exponentialBackoff
+		}
+	}
+
+	// wait between half and the full backoff
+	halfBackoff := backoff / 2
+
+	//nolint:gosec // the jitter doesn't need to be cryptographically secure
+	return halfBackoff + time.Duration(rand.Int63n(int64(backoff-halfBackoff)+1))
+}
+
+// newProtocolParametersMilestoneOpt creates the milestone option that updates the protocol parameters at the target index.
+func newProtocolParametersMilestoneOpt(targetIndex iotago.MilestoneIndex, params *iotago.ProtocolParameters) (*iotago.ProtocolParamsMilestoneOpt, error) {
+	if params == nil {
+		return nil, fmt.Errorf("%w: no protocol parameters given for target index %d", ErrInvalidProtocolParametersUpdate, targetIndex)
+	}
+
+	if targetIndex == 0 {
+		return nil, fmt.Errorf("%w: no target index given", ErrInvalidProtocolParametersUpdate)
+	}
+
+	paramsBytes, err := params.Serialize(serializer.DeSeriModePerformValidation, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProtocolParametersUpdate, err)
+	}
+
+	if len(paramsBytes) > iotago.MaxParamsLength {
+		return nil, fmt.Errorf("%w: serialized protocol parameters have %d bytes, maximum: %d bytes", ErrInvalidProtocolParametersUpdate, len(paramsBytes), iotago.MaxParamsLength)
+	}
+
+	return &iotago.ProtocolParamsMilestoneOpt{
+		TargetMilestoneIndex: targetIndex,
+		ProtocolVersion:      params.Version,
+		Params:               paramsBytes,
+	}, nil
+}
+
+// checkProtocolParametersUpdateTarget checks that the target index of a pending protocol parameters update is in the future.
+func checkProtocolParametersUpdateTarget(opt *iotago.ProtocolParamsMilestoneOpt, latestMilestoneIndex iotago.MilestoneIndex) error {
+	if opt == nil || opt.TargetMilestoneIndex > latestMilestoneIndex {
+		return nil
+	}
+
+	return fmt.Errorf("%w: target index %d is not in the future, latest milestone: %d", ErrInvalidProtocolParametersUpdate, opt.TargetMilestoneIndex, latestMilestoneIndex)
+}
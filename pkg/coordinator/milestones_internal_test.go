@@ -0,0 +1,81 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestSigningRetryBackoff(t *testing.T) {
+	coo := &Coordinator{opts: &Options{signingRetryTimeout: time.Second}}
+
+	// without a maximum the signing retry timeout is used
+	for attempt := 0; attempt < 5; attempt++ {
+		require.Equal(t, time.Second, coo.signingRetryBackoff(attempt))
+	}
+
+	coo.opts.signingMaxBackoff = 10 * time.Second
+	for attempt, expectedBackoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		backoff := coo.signingRetryBackoff(attempt)
+		require.GreaterOrEqual(t, backoff, expectedBackoff/2)
+		require.LessOrEqual(t, backoff, expectedBackoff)
+	}
+
+	// the backoff doesn't overflow for many attempts
+	backoff := coo.signingRetryBackoff(100)
+	require.GreaterOrEqual(t, backoff, 5*time.Second)
+	require.LessOrEqual(t, backoff, 10*time.Second)
+}
+
+func TestCheckpointParents(t *testing.T) {
+	blockID := func(b byte) iotago.BlockID {
+		return iotago.BlockID{b}
+	}
+
+	sortedTips := sortedUniqueBlockIDs(iotago.BlockIDs{blockID(5), blockID(1), blockID(3), blockID(5), blockID(1)})
+	require.Equal(t, iotago.BlockIDs{blockID(1), blockID(3), blockID(5)}, sortedTips)
+
+	// the previous checkpoint block is inserted at its lexical position
+	require.Equal(t, iotago.BlockIDs{blockID(0), blockID(1), blockID(3), blockID(5)}, checkpointParents(blockID(0), sortedTips))
+	require.Equal(t, iotago.BlockIDs{blockID(1), blockID(2), blockID(3), blockID(5)}, checkpointParents(blockID(2), sortedTips))
+	require.Equal(t, iotago.BlockIDs{blockID(1), blockID(3), blockID(5), blockID(9)}, checkpointParents(blockID(9), sortedTips))
+
+	// the previous checkpoint block is not added twice
+	require.Equal(t, sortedTips, checkpointParents(blockID(3), sortedTips))
+
+	// the tips are not modified
+	require.Equal(t, iotago.BlockIDs{blockID(1), blockID(3), blockID(5)}, sortedTips)
+}
+
+func TestVerifyMilestoneParents(t *testing.T) {
+	parents := iotago.BlockIDs{{1}, {2}, {3}}
+	newMilestoneBlock := func(milestoneParents iotago.BlockIDs, blockParents iotago.BlockIDs) *iotago.Block {
+		return &iotago.Block{
+			Parents: blockParents,
+			Payload: &iotago.Milestone{Parents: milestoneParents},
+		}
+	}
+
+	require.NoError(t, verifyMilestoneParents(parents, newMilestoneBlock(parents, parents)))
+
+	// the white flag parents were not sorted
+	unsortedParents := iotago.BlockIDs{{2}, {1}, {3}}
+	require.ErrorIs(t, verifyMilestoneParents(unsortedParents, newMilestoneBlock(unsortedParents, unsortedParents)), ErrMilestoneParentsMismatch)
+
+	// the white flag parents contain duplicates
+	duplicateParents := iotago.BlockIDs{{1}, {1}, {3}}
+	require.ErrorIs(t, verifyMilestoneParents(duplicateParents, newMilestoneBlock(duplicateParents, duplicateParents)), ErrMilestoneParentsMismatch)
+
+	// the milestone was sorted differently than the white flag parents
+	require.ErrorIs(t, verifyMilestoneParents(parents, newMilestoneBlock(unsortedParents, parents)), ErrMilestoneParentsMismatch)
+	require.ErrorIs(t, verifyMilestoneParents(parents, newMilestoneBlock(parents, unsortedParents)), ErrMilestoneParentsMismatch)
+
+	// the milestone misses a parent
+	require.ErrorIs(t, verifyMilestoneParents(parents, newMilestoneBlock(parents[:2], parents)), ErrMilestoneParentsMismatch)
+
+	// the block doesn't contain a milestone
+	require.ErrorIs(t, verifyMilestoneParents(parents, &iotago.Block{Parents: parents}), ErrMilestoneParentsMismatch)
+}
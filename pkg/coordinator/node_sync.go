@@ -0,0 +1,40 @@
+package coordinator
+
+import (
+	"time"
+)
+
+// nodeSyncCheck is the result of a check whether the node connected to the coordinator is synced.
+type nodeSyncCheck struct {
+	// whether the node was synced.
+	synced bool
+	// the time of the check.
+	checkedAt time.Time
+}
+
+// NodeSynced returns whether the node connected to the coordinator is synced,
+// so callers don't need to attempt an issuance and check the returned error for common.ErrNodeNotSynced.
+// The result is remembered, see LastNodeSyncCheck.
+func (coo *Coordinator) NodeSynced() bool {
+	synced := coo.isNodeSynced()
+
+	coo.lastNodeSyncCheckLock.Lock()
+	defer coo.lastNodeSyncCheckLock.Unlock()
+
+	coo.lastNodeSyncCheck = nodeSyncCheck{
+		synced:    synced,
+		checkedAt: coo.opts.clock.Now(),
+	}
+
+	return synced
+}
+
+// LastNodeSyncCheck returns the result and the time of the last check whether the node is synced,
+// including the checks before issuing milestones and checkpoints, without asking the node again.
+// The time is zero if the node was not checked yet.
+func (coo *Coordinator) LastNodeSyncCheck() (bool, time.Time) {
+	coo.lastNodeSyncCheckLock.RLock()
+	defer coo.lastNodeSyncCheckLock.RUnlock()
+
+	return coo.lastNodeSyncCheck.synced, coo.lastNodeSyncCheck.checkedAt
+}
@@ -0,0 +1,88 @@
+package coordinator
+
+import (
+	"time"
+)
+
+// OptionsInfo holds the effective options of the coordinator, including the applied defaults.
+// It is a copy, so changing it doesn't affect the coordinator.
+type OptionsInfo struct {
+	// the path to the state file of the coordinator.
+	StateFilePath string
+	// the amount of previous state files that are kept as backups (0 = disabled).
+	StateBackups int
+	// the current interval milestones are issued.
+	MilestoneInterval time.Duration
+	// the fraction of the interval that is randomly added or subtracted (0 = disabled).
+	IntervalJitter float64
+	// the minimum interval milestones are issued if the interval is adaptive.
+	AdaptiveIntervalMin time.Duration
+	// the maximum interval milestones are issued if the interval is adaptive (0 = disabled).
+	AdaptiveIntervalMax time.Duration
+	// the amount of times to retry signing.
+	SigningRetryAmount int
+	// the timeout between signing retries.
+	SigningRetryTimeout time.Duration
+	// the maximum time between signing retries (0 = fixed signing retry timeout).
+	SigningMaxBackoff time.Duration
+	// the maximum amount of public keys signing a milestone concurrently (0 or 1 = all keys in a single call).
+	SigningParallelism int
+	// the amount of attempts to send a milestone block.
+	SendBlockRetryAttempts int
+	// the time to wait between attempts to send a milestone block.
+	SendBlockRetryBackoff time.Duration
+	// the amount of attempts to write the state file after a milestone was sent.
+	StateWriteRetryAttempts int
+	// the time to wait between attempts to write the state file.
+	StateWriteRetryBackoff time.Duration
+	// the amount of checkpoints after which a milestone is forced (0 = disabled).
+	ForceMilestoneAfterCheckpoints int
+	// the maximum amount of checkpoints issued between two milestones (0 = unlimited).
+	MaxCheckpointsPerInterval int
+	// how the blocks of a checkpoint reference each other.
+	CheckpointStrategy CheckpointStrategy
+	// the maximum amount of parents of a block (0 = protocol default).
+	MaxParentsCount int
+	// whether the quorum is used to check the ledger state.
+	QuorumEnabled bool
+	// whether receipts of the migrator service are included in milestones.
+	MigratorEnabled bool
+	// whether an already issued milestone is adopted after a crash.
+	CrashRecoveryEnabled bool
+	// the amount of issued milestones kept in the history (0 = disabled).
+	HistorySize int
+	// the age after which only the index and the timestamp of milestones in the history are kept (0 = disabled).
+	HistoryCompactAge time.Duration
+	// the maximum duration of the white flag computation of the merkle roots (0 = disabled).
+	MerkleComputeTimeout time.Duration
+}
+
+// OptionsSnapshot returns the effective options of the coordinator, e.g. to confirm that overrides took effect.
+func (coo *Coordinator) OptionsSnapshot() OptionsInfo {
+	return OptionsInfo{
+		StateFilePath:                  coo.opts.stateFilePath,
+		StateBackups:                   coo.opts.stateBackups,
+		MilestoneInterval:              coo.baseInterval(),
+		IntervalJitter:                 coo.opts.intervalJitter,
+		AdaptiveIntervalMin:            coo.opts.adaptiveIntervalMin,
+		AdaptiveIntervalMax:            coo.opts.adaptiveIntervalMax,
+		SigningRetryAmount:             coo.opts.signingRetryAmount,
+		SigningRetryTimeout:            coo.opts.signingRetryTimeout,
+		SigningMaxBackoff:              coo.opts.signingMaxBackoff,
+		SigningParallelism:             coo.opts.signingParallelism,
+		SendBlockRetryAttempts:         coo.opts.sendBlockRetryAttempts,
+		SendBlockRetryBackoff:          coo.opts.sendBlockRetryBackoff,
+		StateWriteRetryAttempts:        coo.opts.stateWriteRetryAttempts,
+		StateWriteRetryBackoff:         coo.opts.stateWriteRetryBackoff,
+		ForceMilestoneAfterCheckpoints: coo.opts.forceMilestoneAfterCheckpoints,
+		MaxCheckpointsPerInterval:      coo.opts.maxCheckpointsPerInterval,
+		CheckpointStrategy:             coo.opts.checkpointStrategy,
+		MaxParentsCount:                coo.opts.maxParentsCount,
+		QuorumEnabled:                  coo.currentQuorum() != nil,
+		MigratorEnabled:                coo.MigratorEnabled(),
+		CrashRecoveryEnabled:           coo.opts.milestoneExistsFunc != nil,
+		HistorySize:                    coo.opts.historySize,
+		HistoryCompactAge:              coo.opts.historyCompactAge,
+		MerkleComputeTimeout:           coo.opts.merkleComputeTimeout,
+	}
+}
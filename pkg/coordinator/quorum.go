@@ -2,6 +2,7 @@ package coordinator
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/semaphore"
 
 	"github.com/iotaledger/hive.go/core/syncutils"
 	"github.com/iotaledger/hornet/v2/pkg/common"
@@ -21,18 +23,77 @@ var (
 	ErrQuorumMerkleTreeHashMismatch = errors.New("coordinator quorum merkle tree hash mismatch")
 	// ErrQuorumGroupNoAnswer is fired when none of the clients in a quorum group answers.
 	ErrQuorumGroupNoAnswer = errors.New("coordinator quorum group did not answer in time")
+	// ErrQuorumNoAnswer is fired when none of the quorum groups answers and the quorum fails closed.
+	ErrQuorumNoAnswer = errors.New("coordinator quorum did not answer in time")
+	// ErrQuorumGroupsNotFound is returned if the quorum is enabled without any groups.
+	ErrQuorumGroupsNotFound = errors.New("coordinator quorum groups not found")
+	// ErrQuorumGroupWithoutNodes is returned if a quorum group contains no nodes.
+	ErrQuorumGroupWithoutNodes = errors.New("coordinator quorum group contains no nodes")
+	// ErrQuorumWeightThresholdNotReached is fired when the agreeing clients in a quorum group don't reach the weight threshold.
+	ErrQuorumWeightThresholdNotReached = errors.New("coordinator quorum group did not reach the weight threshold")
+	// ErrQuorumWeightThresholdUnreachable is returned if the weight threshold is larger than the total weight of a quorum group.
+	ErrQuorumWeightThresholdUnreachable = errors.New("coordinator quorum weight threshold exceeds the total weight of a group")
+	// ErrQuorumClockSkew is reported when the clock of a client in the quorum differs too much from the milestone timestamp.
+	ErrQuorumClockSkew = errors.New("coordinator quorum client clock skew exceeds the threshold")
 )
 
+// QuorumMismatchError is returned if a client in the quorum computed different merkle roots than the coordinator.
+// It wraps ErrQuorumMerkleTreeHashMismatch.
+type QuorumMismatchError struct {
+	// name of the quorum group the client is member of.
+	Group string
+	// optional alias of the quorum client.
+	Alias string
+	// baseURL of the quorum client.
+	BaseURL string
+	// the merkle roots computed by the coordinator.
+	CooMerkleRoots MilestoneMerkleRoots
+	// the merkle roots computed by the quorum client.
+	NodeMerkleRoots MilestoneMerkleRoots
+}
+
+func (e *QuorumMismatchError) Error() string {
+	return fmt.Sprintf("%s, group: %s, alias: %s, baseURL: %s, coo applied: %s, node applied: %s, coo inclusion: %s, node inclusion: %s",
+		ErrQuorumMerkleTreeHashMismatch,
+		e.Group,
+		e.Alias,
+		e.BaseURL,
+		iotago.EncodeHex(e.CooMerkleRoots.AppliedMerkleRoot[:]),
+		iotago.EncodeHex(e.NodeMerkleRoots.AppliedMerkleRoot[:]),
+		iotago.EncodeHex(e.CooMerkleRoots.InclusionMerkleRoot[:]),
+		iotago.EncodeHex(e.NodeMerkleRoots.InclusionMerkleRoot[:]),
+	)
+}
+
+func (e *QuorumMismatchError) Unwrap() error {
+	return ErrQuorumMerkleTreeHashMismatch
+}
+
 // QuorumClientConfig holds the configuration of a quorum client.
 type QuorumClientConfig struct {
 	// optional alias of the quorum client.
 	Alias string `json:"alias" koanf:"alias"`
-	// baseURL of the quorum client.
+	// baseURL of the quorum client, either a http(s) URL or a unix domain socket (unix:///path/to/socket).
 	BaseURL string `json:"baseUrl" koanf:"baseUrl"`
 	// optional username for basic auth.
 	Username string `json:"username" koanf:"username"`
 	// optional password for basic auth.
 	Password string `json:"password" koanf:"password"`
+	// optional bearer token (e.g. JWT) used for authorization.
+	// if a token is given, it takes precedence over basic auth.
+	Token string `json:"token" koanf:"token"`
+	// optional timeout of the quorum client, the quorum timeout is used if not set.
+	Timeout time.Duration `json:"timeout" koanf:"timeout"`
+	// optional path to a PEM encoded CA bundle used to verify the certificate of the quorum client.
+	CAFile string `json:"caFile" koanf:"caFile"`
+	// optional path to a PEM encoded client certificate for mutual TLS.
+	CertFile string `json:"certFile" koanf:"certFile"`
+	// optional path to the PEM encoded private key of the client certificate for mutual TLS.
+	KeyFile string `json:"keyFile" koanf:"keyFile"`
+	// optional TLS configuration used as a base for the settings above.
+	TLSConfig *tls.Config `json:"-" koanf:"-"`
+	// optional weight of the quorum client if a weight threshold is used, defaults to 1.
+	Weight int `json:"weight" koanf:"weight"`
 }
 
 // QuorumClientStatistic holds statistics of a quorum client.
@@ -47,18 +108,51 @@ type QuorumClientStatistic struct {
 	ResponseTimeSeconds float64
 	// error of last whiteflag API call.
 	Error error
+	// state of the circuit breaker of the client.
+	CircuitBreakerState CircuitBreakerState
+	// correlation ID of the last whiteflag API call, which is sent to the client in the X-Correlation-ID header.
+	CorrelationID string
+	// last measured difference between the milestone timestamp and the clock of the client,
+	// positive if the clock of the coordinator is ahead (0 if the clock skew check is disabled).
+	ClockSkew time.Duration
+}
+
+// QuorumGroupMember holds the static configuration of a quorum client.
+type QuorumGroupMember struct {
+	// optional alias of the quorum client.
+	Alias string
+	// baseURL of the quorum client.
+	BaseURL string
+	// weight of the quorum client if a weight threshold is used.
+	Weight int
+	// timeout of the requests to the quorum client.
+	Timeout time.Duration
 }
 
 // QuorumFinishedResult holds statistics of a finished quorum.
 type QuorumFinishedResult struct {
+	// the duration of the quorum check.
 	Duration time.Duration
-	Err      error
+	// the error of the quorum check, nil if it succeeded.
+	Err error
+	// the time the quorum check finished.
+	Timestamp time.Time
 }
 
 // quorumGroupEntry holds the api and statistics of a quorum client.
 type quorumGroupEntry struct {
-	api   *nodeclient.Client
-	stats *QuorumClientStatistic
+	api     *nodeclient.Client
+	timeout time.Duration
+	weight  int
+	breaker *circuitBreaker
+	stats   *QuorumClientStatistic
+}
+
+// quorumNodeResult holds the response of a quorum client.
+type quorumNodeResult struct {
+	entry         *quorumGroupEntry
+	correlationID string
+	response      *nodeclient.ComputeWhiteFlagMutationsResponse
 }
 
 // quorum is used to check the correct ledger state of the coordinator.
@@ -67,35 +161,76 @@ type quorum struct {
 	Groups map[string][]*quorumGroupEntry
 	// the maximim timeout of a quorum request.
 	Timeout time.Duration
-
+	// the weight of agreeing clients needed to accept the merkle roots of a group (0 = all answering clients need to agree).
+	weightThreshold int
+	// the optional semaphore that limits the requests in flight across all groups.
+	requestsSemaphore *semaphore.Weighted
+	// the optional cache of successful quorum checks.
+	resultCache *quorumResultCache
+	// whether a quorum without any answering group is a critical error.
+	failClosed bool
+	// the clock skew to a client that is reported as an error of the client (0 = disabled).
+	clockSkewThreshold time.Duration
+	// whether the requests to the clients and their responses are compressed with gzip.
+	compression bool
+
+	// used to protect the statistics of the quorum clients.
 	quorumStatsLock syncutils.RWMutex
 }
 
 // newQuorum creates a new quorum, which is used to check the correct ledger state of the coordinator.
-// If no groups are given, nil is returned.
-func newQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration) *quorum {
+// The clients share the given transport, the default transport is used if it is nil.
+// Returns an error if the configuration is invalid or the TLS configuration of a client can't be loaded.
+func newQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Duration, transport *http.Transport) (*quorum, error) {
 	if len(quorumGroups) == 0 {
-		panic("coordinator quorum groups not found")
+		return nil, ErrQuorumGroupsNotFound
 	}
 
 	groups := make(map[string][]*quorumGroupEntry)
 	for groupName, groupNodes := range quorumGroups {
 		if len(groupNodes) == 0 {
-			panic(fmt.Sprintf("invalid coo quorum group: %s, no nodes given", groupName))
+			return nil, fmt.Errorf("%w: %s", ErrQuorumGroupWithoutNodes, groupName)
 		}
 
 		groups[groupName] = make([]*quorumGroupEntry, len(groupNodes))
 		for i, client := range groupNodes {
+			if client == nil {
+				return nil, fmt.Errorf("invalid coordinator quorum group: %s, node %d is empty", groupName, i)
+			}
+
 			var userInfo *url.Userinfo
-			if client.Username != "" || client.Password != "" {
+			if client.Token == "" && (client.Username != "" || client.Password != "") {
 				userInfo = url.UserPassword(client.Username, client.Password)
 			}
 
+			clientTimeout := timeout
+			if client.Timeout > 0 {
+				clientTimeout = client.Timeout
+			}
+
+			requestBaseURL, socketPath, err := parseQuorumClientBaseURL(client.BaseURL)
+			if err != nil {
+				return nil, err
+			}
+
+			httpClient, err := newQuorumHTTPClient(client, transport, socketPath)
+			if err != nil {
+				return nil, err
+			}
+
+			weight := 1
+			if client.Weight > 0 {
+				weight = client.Weight
+			}
+
 			groups[groupName][i] = &quorumGroupEntry{
-				api: nodeclient.New(client.BaseURL,
-					nodeclient.WithHTTPClient(&http.Client{Timeout: timeout}),
+				api: nodeclient.New(requestBaseURL,
+					nodeclient.WithHTTPClient(httpClient),
 					nodeclient.WithUserInfo(userInfo),
 				),
+				timeout: clientTimeout,
+				weight:  weight,
+				breaker: &circuitBreaker{},
 				stats: &QuorumClientStatistic{
 					Group:   groupName,
 					Alias:   client.Alias,
@@ -108,116 +243,269 @@ func newQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Durat
 	return &quorum{
 		Groups:  groups,
 		Timeout: timeout,
-	}
+	}, nil
 }
 
 // checkMerkleTreeHashQuorumGroup asks all nodes in a quorum group for their merkle tree hash based on the given parents.
 // Returns non-critical and critical errors.
 // If no node of the group answers, a non-critical error is returned.
 // If one of the nodes returns a different hash, a critical error is returned.
-func (q *quorum) checkMerkleTreeHashQuorumGroup(cooMerkleProof *MilestoneMerkleRoots,
+// If a weight threshold is set, the hash is accepted as soon as the weight of the agreeing nodes reaches the threshold.
+// A different hash is only a critical error if the threshold is not reached.
+// All requests of the group are cancelled as soon as the quorum context is done.
+func (q *quorum) checkMerkleTreeHashQuorumGroup(quorumCtx context.Context,
+	cooMerkleProof *MilestoneMerkleRoots,
 	groupName string,
 	quorumGroupEntries []*quorumGroupEntry,
 	wg *sync.WaitGroup,
-	quorumDoneChan chan struct{},
 	quorumErrChan chan error,
 	index iotago.MilestoneIndex,
 	timestamp uint32,
 	parents iotago.BlockIDs,
 	previousMilestoneID iotago.MilestoneID,
-	onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error)) {
+	onGroupEntryError func(groupName string, entry *quorumGroupEntry, correlationID string, err error)) {
 	// mark the group as done at the end
 	defer wg.Done()
 
-	// cancel the quorum after a certain timeout
-	ctx, cancel := context.WithTimeout(context.Background(), q.Timeout)
+	// cancel the quorum after a certain timeout.
+	// clients with a larger timeout extend the timeout of the whole group.
+	groupTimeout := q.Timeout
+	for _, entry := range quorumGroupEntries {
+		if entry.timeout > groupTimeout {
+			groupTimeout = entry.timeout
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(quorumCtx, groupTimeout)
 	defer cancel()
 
 	// create buffered channels, so the go routines will not be dangling if no receiver waits for the results anymore
 	// garbage collector will take care if the channels are not used anymore. no need to close manually
-	nodeResultChan := make(chan *nodeclient.ComputeWhiteFlagMutationsResponse, len(quorumGroupEntries))
+	nodeResultChan := make(chan *quorumNodeResult, len(quorumGroupEntries))
 	nodeErrorChan := make(chan error, len(quorumGroupEntries))
 
 	for _, entry := range quorumGroupEntries {
-		go func(entry *quorumGroupEntry, nodeResultChan chan *nodeclient.ComputeWhiteFlagMutationsResponse, nodeErrorChan chan error) {
+		q.quorumStatsLock.Lock()
+		allowed := entry.breaker.allow(time.Now())
+		entry.stats.CircuitBreakerState = entry.breaker.state
+		q.quorumStatsLock.Unlock()
+
+		if !allowed {
+			// skip clients that failed too often
+			nodeErrorChan <- ErrQuorumClientCircuitOpen
+
+			continue
+		}
+
+		go func(entry *quorumGroupEntry, nodeResultChan chan *quorumNodeResult, nodeErrorChan chan error) {
+			// the timeout of the client is only applied via the context of the request,
+			// the http client has no timeout of its own that could end the request earlier
+			requestCtx, requestCancel := context.WithTimeout(ctx, entry.timeout)
+			defer requestCancel()
+
+			// the correlation ID is sent to the client, so the logs of both sides can be matched
+			correlationID := newQuorumCorrelationID()
+			requestCtx = withQuorumCorrelationID(requestCtx, correlationID)
+			if q.compression {
+				requestCtx = withQuorumCompression(requestCtx)
+			}
+
+			if q.requestsSemaphore != nil {
+				// waiting for a free slot counts against the timeout of the request
+				if err := q.requestsSemaphore.Acquire(requestCtx, 1); err != nil {
+					nodeErrorChan <- err
+
+					return
+				}
+				defer q.requestsSemaphore.Release(1)
+			}
+
 			ts := time.Now()
 
-			response, err := entry.api.ComputeWhiteFlagMutations(ctx, index, timestamp, parents, previousMilestoneID)
+			response, err := entry.api.ComputeWhiteFlagMutations(requestCtx, index, timestamp, parents, previousMilestoneID)
+			if err != nil && (quorumCtx.Err() != nil || errors.Is(ctx.Err(), context.Canceled)) {
+				// the quorum was aborted or the group already returned, the failed request is not the fault of the client
+				nodeErrorChan <- err
+
+				return
+			}
 
 			// set the stats for the node
+			q.quorumStatsLock.Lock()
 			entry.stats.ResponseTimeSeconds = time.Since(ts).Seconds()
 			entry.stats.Error = err
+			entry.stats.CorrelationID = correlationID
+			entry.breaker.recordResult(time.Now(), err)
+			entry.stats.CircuitBreakerState = entry.breaker.state
+			q.quorumStatsLock.Unlock()
 
 			if err != nil {
 				if onGroupEntryError != nil {
-					onGroupEntryError(groupName, entry, err)
+					onGroupEntryError(groupName, entry, correlationID, err)
 				}
 				nodeErrorChan <- err
 
 				return
 			}
-			nodeResultChan <- response
+
+			if q.clockSkewThreshold > 0 {
+				// the clock skew doesn't affect the merkle roots, so it is only reported
+				if err := q.checkClockSkew(requestCtx, entry, time.Unix(int64(timestamp), 0)); err != nil && onGroupEntryError != nil {
+					onGroupEntryError(groupName, entry, correlationID, err)
+				}
+			}
+
+			nodeResultChan <- &quorumNodeResult{entry: entry, correlationID: correlationID, response: response}
 		}(entry, nodeResultChan, nodeErrorChan)
 	}
 
 	//nolint:ifshort // false positive
 	validResults := 0
+	agreeingWeight := 0
+	var mismatchErr *QuorumMismatchError
 QuorumLoop:
 	for i := 0; i < len(quorumGroupEntries); i++ {
 		// we wait either until the channel got closed or the context is done
 		select {
-		case <-quorumDoneChan:
-			// quorum was aborted
-			return
-
 		case <-nodeErrorChan:
 			// ignore errors of single nodes
 			continue
 
-		case nodeWhiteFlagResponse := <-nodeResultChan:
+		case nodeResult := <-nodeResultChan:
+			nodeWhiteFlagResponse := nodeResult.response
 			if cooMerkleProof.AppliedMerkleRoot != nodeWhiteFlagResponse.AppliedMerkleRoot ||
 				cooMerkleProof.InclusionMerkleRoot != nodeWhiteFlagResponse.InclusionMerkleRoot {
-				// mismatch of the merkle tree hash of the node => critical error
-				quorumErrChan <- common.CriticalError(ErrQuorumMerkleTreeHashMismatch)
+				mismatchErr = &QuorumMismatchError{
+					Group:          groupName,
+					Alias:          nodeResult.entry.stats.Alias,
+					BaseURL:        nodeResult.entry.stats.BaseURL,
+					CooMerkleRoots: *cooMerkleProof,
+					NodeMerkleRoots: MilestoneMerkleRoots{
+						InclusionMerkleRoot: nodeWhiteFlagResponse.InclusionMerkleRoot,
+						AppliedMerkleRoot:   nodeWhiteFlagResponse.AppliedMerkleRoot,
+					},
+				}
 
-				return
+				if q.weightThreshold <= 0 {
+					// mismatch of the merkle tree hash of the node => critical error
+					quorumErrChan <- common.CriticalError(mismatchErr)
+
+					return
+				}
+
+				// the mismatch is only critical if the agreeing nodes don't reach the threshold,
+				// but the mismatching client is still reported
+				if onGroupEntryError != nil {
+					onGroupEntryError(groupName, nodeResult.entry, nodeResult.correlationID, mismatchErr)
+				}
+
+				continue
 			}
 			validResults++
+			agreeingWeight += nodeResult.entry.weight
+
+			if q.weightThreshold > 0 && agreeingWeight >= q.weightThreshold {
+				// enough nodes agreed, the remaining answers are not needed
+				return
+			}
 
 		case <-ctx.Done():
+			if quorumCtx.Err() != nil {
+				// quorum was aborted
+				return
+			}
+
 			// quorum timeout reached
 			break QuorumLoop
 		}
 	}
 
+	if q.weightThreshold > 0 {
+		switch {
+		case mismatchErr != nil:
+			// the agreeing nodes didn't outweigh the mismatch => critical error
+			quorumErrChan <- common.CriticalError(mismatchErr)
+		case validResults == 0:
+			// no node of the group answered, return a non-critical error.
+			quorumErrChan <- common.SoftError(ErrQuorumGroupNoAnswer)
+		default:
+			quorumErrChan <- common.SoftError(fmt.Errorf("%w, group: %s, weight: %d, threshold: %d", ErrQuorumWeightThresholdNotReached, groupName, agreeingWeight, q.weightThreshold))
+		}
+
+		return
+	}
+
 	if validResults == 0 {
 		// no node of the group answered, return a non-critical error.
 		quorumErrChan <- common.SoftError(ErrQuorumGroupNoAnswer)
 	}
 }
 
+// checkClockSkew compares the milestone timestamp with the clock of the client, which is taken from the Date header of its info API.
+// The node sets the Date header at some point during the request, so half of the round trip is added to it.
+// The Date header has a resolution of one second, which limits the accuracy of the measured skew.
+// Returns an error if the skew exceeds the threshold or can't be measured.
+func (q *quorum) checkClockSkew(ctx context.Context, entry *quorumGroupEntry, milestoneTimestamp time.Time) error {
+	var responseDate time.Time
+	requestStart := time.Now()
+	if _, err := entry.api.Info(withQuorumResponseDateRecorder(ctx, &responseDate)); err != nil {
+		return fmt.Errorf("unable to measure the clock skew: %w", err)
+	}
+	if responseDate.IsZero() {
+		return errors.New("unable to measure the clock skew: no Date header in the response")
+	}
+
+	clockSkew := milestoneTimestamp.Sub(responseDate.Add(time.Since(requestStart) / 2))
+
+	q.quorumStatsLock.Lock()
+	entry.stats.ClockSkew = clockSkew
+	q.quorumStatsLock.Unlock()
+
+	if clockSkew > q.clockSkewThreshold || clockSkew < -q.clockSkewThreshold {
+		return fmt.Errorf("%w: %v, threshold: %v", ErrQuorumClockSkew, clockSkew.Round(time.Millisecond), q.clockSkewThreshold)
+	}
+
+	return nil
+}
+
 // checkMerkleTreeHash asks all nodes in the quorum for their merkle tree hash based on the given parents.
 // Returns non-critical and critical errors.
-// If no node of a certain group answers, a non-critical error is returned.
+// If no node of a certain group answers, a non-critical error is returned,
+// unless the quorum fails closed and no group answers at all, which is a critical error.
 // If one of the nodes returns a different hash, a critical error is returned.
+// If the result cache is enabled, the nodes are not asked again for the same inputs after a successful check.
 func (q *quorum) checkMerkleTreeHash(cooMerkleProof *MilestoneMerkleRoots,
 	index iotago.MilestoneIndex,
 	timestamp uint32,
 	parents iotago.BlockIDs,
 	previousMilestoneID iotago.MilestoneID,
-	onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error)) error {
-	q.quorumStatsLock.Lock()
-	defer q.quorumStatsLock.Unlock()
+	onGroupEntryError func(groupName string, entry *quorumGroupEntry, correlationID string, err error)) error {
+	var resultCacheKey quorumResultCacheKey
+	if q.resultCache != nil {
+		resultCacheKey = newQuorumResultCacheKey(cooMerkleProof, index, timestamp, parents, previousMilestoneID)
+		if q.resultCache.contains(index, resultCacheKey) {
+			return nil
+		}
+	}
 
+	// the stats lock is only held while updating the stats of a single entry,
+	// so reading the stats doesn't need to wait for the API calls.
 	wg := &sync.WaitGroup{}
+
+	// the remaining groups are aborted as soon as the quorum returns, e.g. on the first error
+	quorumCtx, quorumCancel := context.WithCancel(context.Background())
+	defer quorumCancel()
+
 	quorumDoneChan := make(chan struct{})
-	quorumErrChan := make(chan error)
+
+	// every group sends at most one error, so the groups don't block if the quorum already returned
+	quorumErrChan := make(chan error, len(q.Groups))
 
 	for groupName, quorumGroupEntries := range q.Groups {
 		wg.Add(1)
 
 		// ask all groups in parallel
-		go q.checkMerkleTreeHashQuorumGroup(cooMerkleProof, groupName, quorumGroupEntries, wg, quorumDoneChan, quorumErrChan, index, timestamp, parents, previousMilestoneID, onGroupEntryError)
+		go q.checkMerkleTreeHashQuorumGroup(quorumCtx, cooMerkleProof, groupName, quorumGroupEntries, wg, quorumErrChan, index, timestamp, parents, previousMilestoneID, onGroupEntryError)
 	}
 
 	go func(wg *sync.WaitGroup, doneChan chan struct{}) {
@@ -228,14 +516,40 @@ func (q *quorum) checkMerkleTreeHash(cooMerkleProof *MilestoneMerkleRoots,
 		close(doneChan)
 	}(wg, quorumDoneChan)
 
-	select {
-	case <-quorumDoneChan:
-		// quorum finished successfully
-		return nil
+	noAnswerGroups := 0
+	for {
+		var err error
+		select {
+		case <-quorumDoneChan:
+			// every group sends its error before it is done, so the errors of the last groups are still buffered
+			select {
+			case err = <-quorumErrChan:
+			default:
+				if noAnswerGroups > 0 {
+					return common.SoftError(ErrQuorumGroupNoAnswer)
+				}
+
+				// quorum finished successfully
+				if q.resultCache != nil {
+					q.resultCache.add(index, resultCacheKey)
+				}
+
+				return nil
+			}
 
-	case err := <-quorumErrChan:
-		// quorum encountered an error
-		return err
+		case err = <-quorumErrChan:
+		}
+
+		if !q.failClosed || !errors.Is(err, ErrQuorumGroupNoAnswer) {
+			// quorum encountered an error
+			return err
+		}
+
+		// wait for the other groups to find out whether the whole quorum didn't answer
+		noAnswerGroups++
+		if noAnswerGroups == len(q.Groups) {
+			return common.CriticalError(fmt.Errorf("%w: none of the %d groups answered", ErrQuorumNoAnswer, noAnswerGroups))
+		}
 	}
 }
 
@@ -254,3 +568,132 @@ func (q *quorum) quorumStatsSnapshot() []QuorumClientStatistic {
 
 	return stats
 }
+
+// topology returns the members of every quorum group in the configured order.
+func (q *quorum) topology() map[string][]QuorumGroupMember {
+	q.quorumStatsLock.RLock()
+	defer q.quorumStatsLock.RUnlock()
+
+	topology := make(map[string][]QuorumGroupMember, len(q.Groups))
+	for groupName, quorumGroup := range q.Groups {
+		members := make([]QuorumGroupMember, len(quorumGroup))
+		for i, entry := range quorumGroup {
+			members[i] = QuorumGroupMember{
+				Alias:   entry.stats.Alias,
+				BaseURL: entry.stats.BaseURL,
+				Weight:  entry.weight,
+				Timeout: entry.timeout,
+			}
+		}
+		topology[groupName] = members
+	}
+
+	return topology
+}
+
+// adoptStats takes over the statistics of clients with the same baseURL.
+func (q *quorum) adoptStats(stats []QuorumClientStatistic) {
+	q.quorumStatsLock.Lock()
+	defer q.quorumStatsLock.Unlock()
+
+	statsByBaseURL := make(map[string]QuorumClientStatistic, len(stats))
+	for _, stat := range stats {
+		statsByBaseURL[stat.BaseURL] = stat
+	}
+
+	for _, quorumGroup := range q.Groups {
+		for _, entry := range quorumGroup {
+			if stat, exists := statsByBaseURL[entry.stats.BaseURL]; exists {
+				entry.stats.ResponseTimeSeconds = stat.ResponseTimeSeconds
+				entry.stats.Error = stat.Error
+				entry.stats.CorrelationID = stat.CorrelationID
+				entry.stats.ClockSkew = stat.ClockSkew
+			}
+		}
+	}
+}
+
+// setCircuitBreaker configures the circuit breakers of all quorum clients.
+// A failureThreshold of 0 disables the circuit breakers.
+func (q *quorum) setCircuitBreaker(failureThreshold int, cooldown time.Duration) {
+	q.quorumStatsLock.Lock()
+	defer q.quorumStatsLock.Unlock()
+
+	for _, quorumGroup := range q.Groups {
+		for _, entry := range quorumGroup {
+			entry.breaker.failureThreshold = failureThreshold
+			entry.breaker.cooldown = cooldown
+		}
+	}
+}
+
+// applyOptions applies the quorum options of the coordinator.
+func (q *quorum) applyOptions(opts *Options) {
+	q.setCircuitBreaker(opts.quorumCircuitBreakerThreshold, opts.quorumCircuitBreakerCooldown)
+	q.setWeightThreshold(opts.quorumWeightThreshold)
+	q.setConcurrencyLimit(opts.quorumConcurrencyLimit)
+	q.setResultCache(opts.quorumResultCacheSize, opts.quorumResultCacheTTL)
+	q.setFailClosed(opts.quorumFailClosed)
+	q.setClockSkewThreshold(opts.quorumClockSkewThreshold)
+	q.setCompression(opts.quorumCompression)
+}
+
+// setWeightThreshold sets the weight of agreeing clients needed to accept the merkle roots of a group.
+// A weightThreshold of 0 requires all answering clients to agree.
+func (q *quorum) setWeightThreshold(weightThreshold int) {
+	q.weightThreshold = weightThreshold
+}
+
+// validateWeightThreshold checks that every group can reach the weight threshold.
+func (q *quorum) validateWeightThreshold() error {
+	if q.weightThreshold <= 0 {
+		return nil
+	}
+
+	for groupName, quorumGroupEntries := range q.Groups {
+		totalWeight := 0
+		for _, entry := range quorumGroupEntries {
+			totalWeight += entry.weight
+		}
+
+		if q.weightThreshold > totalWeight {
+			return fmt.Errorf("%w: group: %s, weight: %d, threshold: %d", ErrQuorumWeightThresholdUnreachable, groupName, totalWeight, q.weightThreshold)
+		}
+	}
+
+	return nil
+}
+
+// setFailClosed defines whether a quorum without any answering group is a critical error.
+func (q *quorum) setFailClosed(failClosed bool) {
+	q.failClosed = failClosed
+}
+
+// setClockSkewThreshold sets the clock skew to a client that is reported as an error of the client.
+// A clockSkewThreshold of 0 disables the clock skew check.
+func (q *quorum) setClockSkewThreshold(clockSkewThreshold time.Duration) {
+	q.clockSkewThreshold = clockSkewThreshold
+}
+
+// setCompression defines whether the requests to the clients and their responses are compressed with gzip.
+func (q *quorum) setCompression(compression bool) {
+	q.compression = compression
+}
+
+// setConcurrencyLimit limits the amount of requests in flight across all groups.
+// A concurrencyLimit of 0 disables the limit.
+func (q *quorum) setConcurrencyLimit(concurrencyLimit int) {
+	q.requestsSemaphore = nil
+	if concurrencyLimit > 0 {
+		q.requestsSemaphore = semaphore.NewWeighted(int64(concurrencyLimit))
+	}
+}
+
+// setResultCache caches up to size successful quorum checks of the latest milestone index for the given duration.
+// A size or ttl of 0 disables the cache.
+func (q *quorum) setResultCache(size int, ttl time.Duration) {
+	q.resultCache = nil
+	if size > 0 && ttl > 0 {
+		q.resultCache = newQuorumResultCache(size, ttl)
+	}
+}
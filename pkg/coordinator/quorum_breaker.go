@@ -0,0 +1,87 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrQuorumClientCircuitOpen is returned if a quorum client is skipped because its circuit breaker is open.
+	ErrQuorumClientCircuitOpen = errors.New("coordinator quorum client skipped, circuit breaker open")
+)
+
+// CircuitBreakerState is the state of the circuit breaker of a quorum client.
+type CircuitBreakerState int
+
+const (
+	// CircuitBreakerClosed means the quorum client is asked.
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerOpen means the quorum client failed too often and is skipped until the cooldown is over.
+	CircuitBreakerOpen
+	// CircuitBreakerHalfOpen means the cooldown is over and the next request probes whether the quorum client recovered.
+	CircuitBreakerHalfOpen
+)
+
+// String returns the name of the CircuitBreakerState.
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case CircuitBreakerClosed:
+		return "closed"
+	case CircuitBreakerOpen:
+		return "open"
+	case CircuitBreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker skips quorum clients that failed several times in a row.
+type circuitBreaker struct {
+	// the amount of consecutive failures after which the circuit breaker opens (0 = disabled).
+	failureThreshold int
+	// the duration the circuit breaker stays open before a probe is allowed.
+	cooldown time.Duration
+
+	state               CircuitBreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+}
+
+// allow returns whether the quorum client should be asked.
+// An open circuit breaker transitions to half-open after the cooldown.
+func (cb *circuitBreaker) allow(now time.Time) bool {
+	if cb.failureThreshold <= 0 {
+		return true
+	}
+
+	if cb.state == CircuitBreakerOpen {
+		if now.Sub(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = CircuitBreakerHalfOpen
+	}
+
+	return true
+}
+
+// recordResult updates the circuit breaker with the result of a request.
+func (cb *circuitBreaker) recordResult(now time.Time, err error) {
+	if cb.failureThreshold <= 0 {
+		return
+	}
+
+	if err == nil {
+		cb.consecutiveFailures = 0
+		cb.state = CircuitBreakerClosed
+
+		return
+	}
+
+	cb.consecutiveFailures++
+	if cb.state == CircuitBreakerHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+		cb.state = CircuitBreakerOpen
+		cb.openedAt = now
+	}
+}
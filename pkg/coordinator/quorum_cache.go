@@ -0,0 +1,99 @@
+package coordinator
+
+import (
+	"encoding/binary"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/core/lru_cache"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// quorumResultCacheKey identifies a quorum check by its white flag inputs and the merkle roots of the coordinator.
+type quorumResultCacheKey [blake2b.Size256]byte
+
+// newQuorumResultCacheKey hashes the inputs of a quorum check.
+func newQuorumResultCacheKey(cooMerkleProof *MilestoneMerkleRoots, index iotago.MilestoneIndex, timestamp uint32, parents iotago.BlockIDs, previousMilestoneID iotago.MilestoneID) quorumResultCacheKey {
+	data := make([]byte, 0, 8+len(parents)*iotago.BlockIDLength+iotago.MilestoneIDLength+2*iotago.MilestoneMerkleProofLength)
+	data = binary.LittleEndian.AppendUint32(data, index)
+	data = binary.LittleEndian.AppendUint32(data, timestamp)
+	for _, parent := range parents {
+		data = append(data, parent[:]...)
+	}
+	data = append(data, previousMilestoneID[:]...)
+	data = append(data, cooMerkleProof.InclusionMerkleRoot[:]...)
+	data = append(data, cooMerkleProof.AppliedMerkleRoot[:]...)
+
+	return blake2b.Sum256(data)
+}
+
+// quorumResultCache remembers the successful quorum checks of the latest milestone index for a short time,
+// so the quorum is not asked again for the same inputs, e.g. if the same milestone is computed repeatedly.
+type quorumResultCache struct {
+	// the duration a successful quorum check is cached.
+	ttl time.Duration
+	// the cached quorum checks, the values are the expiration times.
+	cache *lru_cache.LRUCache
+	// the milestone index of the cached quorum checks.
+	index iotago.MilestoneIndex
+	// used to invalidate the cache if the milestone index advances.
+	indexLock syncutils.Mutex
+}
+
+// newQuorumResultCache creates a new cache for up to size successful quorum checks.
+func newQuorumResultCache(size int, ttl time.Duration) *quorumResultCache {
+	return &quorumResultCache{
+		ttl:   ttl,
+		cache: lru_cache.NewLRUCache(size),
+	}
+}
+
+// advanceIndex invalidates all cached quorum checks if the milestone index advanced.
+// Returns false if the index belongs to an older milestone, which bypasses the cache.
+func (c *quorumResultCache) advanceIndex(index iotago.MilestoneIndex) bool {
+	c.indexLock.Lock()
+	defer c.indexLock.Unlock()
+
+	if index < c.index {
+		return false
+	}
+
+	if index > c.index {
+		c.cache.DeleteAll()
+		c.index = index
+	}
+
+	return true
+}
+
+// contains returns whether a successful quorum check with the given inputs is cached and not expired yet.
+func (c *quorumResultCache) contains(index iotago.MilestoneIndex, key quorumResultCacheKey) bool {
+	if !c.advanceIndex(index) {
+		return false
+	}
+
+	expiration := c.cache.Get(key)
+	if expiration == nil {
+		return false
+	}
+
+	//nolint:forcetypeassert // only expiration times are stored in the cache
+	if time.Now().After(expiration.(time.Time)) {
+		c.cache.Delete(key)
+
+		return false
+	}
+
+	return true
+}
+
+// add caches a successful quorum check with the given inputs.
+func (c *quorumResultCache) add(index iotago.MilestoneIndex, key quorumResultCacheKey) {
+	if !c.advanceIndex(index) {
+		return
+	}
+
+	c.cache.Set(key, time.Now().Add(c.ttl))
+}
@@ -0,0 +1,309 @@
+package coordinator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// the scheme of quorum clients that are reached via a unix domain socket, e.g. "unix:///var/run/hornet.sock".
+	quorumUnixSocketScheme = "unix"
+	// the base URL of the requests to quorum clients that are reached via a unix domain socket.
+	// the host is ignored, because the transport always dials the socket.
+	quorumUnixSocketBaseURL = "http://unix"
+	// QuorumCorrelationIDHeader is the header that carries the correlation ID of a request to a quorum client.
+	QuorumCorrelationIDHeader = "X-Correlation-ID"
+)
+
+// quorumCorrelationIDContextKey is the context key of the correlation ID of a request to a quorum client.
+type quorumCorrelationIDContextKey struct{}
+
+// quorumCompressionContextKey is the context key that marks a request to a quorum client to be compressed.
+type quorumCompressionContextKey struct{}
+
+// quorumResponseDateContextKey is the context key of the recorder of the Date header of a response of a quorum client.
+type quorumResponseDateContextKey struct{}
+
+// newQuorumCorrelationID generates a random ID, which is sent to a quorum client to correlate the logs of both sides.
+func newQuorumCorrelationID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		// the ID is only used to correlate logs, a missing ID is not worth failing the quorum
+		return ""
+	}
+
+	return hex.EncodeToString(id[:])
+}
+
+// withQuorumCorrelationID returns a copy of the context that carries the given correlation ID.
+func withQuorumCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, quorumCorrelationIDContextKey{}, correlationID)
+}
+
+// quorumCorrelationIDFromContext returns the correlation ID carried by the context, or an empty string.
+func quorumCorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(quorumCorrelationIDContextKey{}).(string)
+
+	return correlationID
+}
+
+// withQuorumResponseDateRecorder returns a copy of the context that records the Date header of the response into responseDate.
+func withQuorumResponseDateRecorder(ctx context.Context, responseDate *time.Time) context.Context {
+	return context.WithValue(ctx, quorumResponseDateContextKey{}, responseDate)
+}
+
+// withQuorumCompression returns a copy of the context that marks the requests to be compressed with gzip.
+func withQuorumCompression(ctx context.Context) context.Context {
+	return context.WithValue(ctx, quorumCompressionContextKey{}, true)
+}
+
+// parseQuorumClientBaseURL returns the base URL of the requests to a quorum client,
+// and the path of the unix domain socket if the client is reached via a unix domain socket.
+func parseQuorumClientBaseURL(baseURL string) (requestBaseURL string, socketPath string, err error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid baseURL of quorum client %s: %w", baseURL, err)
+	}
+
+	if parsedURL.Scheme != quorumUnixSocketScheme {
+		return baseURL, "", nil
+	}
+
+	if parsedURL.Host != "" || parsedURL.Path == "" {
+		return "", "", fmt.Errorf("invalid baseURL of quorum client %s: unix domain sockets must be given as unix:///path/to/socket", baseURL)
+	}
+
+	return quorumUnixSocketBaseURL, parsedURL.Path, nil
+}
+
+// newQuorumHTTPClient creates the http client used to talk to a quorum client.
+// The client uses the given shared transport, or the default transport if it is nil.
+// A client with a custom TLS configuration or a unix domain socket uses a clone of the transport.
+// The client has no timeout, the timeout of a request is defined by its context.
+func newQuorumHTTPClient(config *QuorumClientConfig, sharedTransport *http.Transport, socketPath string) (*http.Client, error) {
+	tlsConfig, err := loadQuorumClientTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if sharedTransport == nil {
+		//nolint:forcetypeassert // the default transport is always a *http.Transport
+		sharedTransport = http.DefaultTransport.(*http.Transport)
+	}
+
+	var transport http.RoundTripper = sharedTransport
+	if tlsConfig != nil || socketPath != "" {
+		clientTransport := sharedTransport.Clone()
+		if tlsConfig != nil {
+			clientTransport.TLSClientConfig = tlsConfig
+		}
+		if socketPath != "" {
+			clientTransport.DialContext = func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+
+				return dialer.DialContext(ctx, "unix", socketPath)
+			}
+		}
+		transport = clientTransport
+	}
+
+	if config.Token != "" {
+		// the token takes precedence over basic auth
+		transport = &headerRoundTripper{
+			next:   transport,
+			header: http.Header{"Authorization": []string{"Bearer " + config.Token}},
+		}
+	}
+
+	return &http.Client{Transport: &correlationIDRoundTripper{next: &responseDateRoundTripper{next: &compressionRoundTripper{next: transport}}}}, nil
+}
+
+// responseDateRoundTripper records the Date header of the response of every request that carries a recorder in its context.
+type responseDateRoundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction and records the Date header of the response.
+func (rt *responseDateRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if responseDate, ok := req.Context().Value(quorumResponseDateContextKey{}).(*time.Time); ok {
+		if date, err := http.ParseTime(res.Header.Get("Date")); err == nil {
+			*responseDate = date
+		}
+	}
+
+	return res, nil
+}
+
+// correlationIDRoundTripper sets the correlation ID header of every request that carries a correlation ID in its context.
+type correlationIDRoundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction with the correlation ID header set.
+func (rt *correlationIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	correlationID := quorumCorrelationIDFromContext(req.Context())
+	if correlationID == "" {
+		return rt.next.RoundTrip(req)
+	}
+
+	// the original request must not be modified
+	req = req.Clone(req.Context())
+	req.Header.Set(QuorumCorrelationIDHeader, correlationID)
+
+	return rt.next.RoundTrip(req)
+}
+
+// compressionRoundTripper compresses the body of every request that is marked to be compressed in its context with gzip,
+// and asks for a gzip compressed response, which is decompressed transparently.
+type compressionRoundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction with a gzip compressed request and response.
+func (rt *compressionRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if compress, _ := req.Context().Value(quorumCompressionContextKey{}).(bool); !compress {
+		return rt.next.RoundTrip(req)
+	}
+
+	// the original request must not be modified
+	compressedReq := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the request body: %w", err)
+		}
+
+		var compressedBody bytes.Buffer
+		gzipWriter := gzip.NewWriter(&compressedBody)
+		if _, err := gzipWriter.Write(body); err != nil {
+			return nil, fmt.Errorf("unable to compress the request body: %w", err)
+		}
+		if err := gzipWriter.Close(); err != nil {
+			return nil, fmt.Errorf("unable to compress the request body: %w", err)
+		}
+
+		compressedBytes := compressedBody.Bytes()
+		compressedReq.Body = io.NopCloser(bytes.NewReader(compressedBytes))
+		compressedReq.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(compressedBytes)), nil
+		}
+		compressedReq.ContentLength = int64(len(compressedBytes))
+		compressedReq.Header.Set("Content-Encoding", "gzip")
+	}
+
+	// the transport doesn't decompress the response if the header is set explicitly
+	compressedReq.Header.Set("Accept-Encoding", "gzip")
+
+	res, err := rt.next.RoundTrip(compressedReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Header.Get("Content-Encoding") != "gzip" {
+		return res, nil
+	}
+
+	gzipReader, err := gzip.NewReader(res.Body)
+	if err != nil {
+		_ = res.Body.Close()
+
+		return nil, fmt.Errorf("unable to decompress the response body: %w", err)
+	}
+
+	res.Body = &gzipReadCloser{Reader: gzipReader, body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+
+	return res, nil
+}
+
+// gzipReadCloser decompresses a response body and closes the underlying body.
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the gzip reader and the underlying body.
+func (r *gzipReadCloser) Close() error {
+	_ = r.Reader.Close()
+
+	return r.body.Close()
+}
+
+// headerRoundTripper adds additional headers to every request.
+type headerRoundTripper struct {
+	next   http.RoundTripper
+	header http.Header
+}
+
+// RoundTrip executes a single HTTP transaction with the additional headers set.
+func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the original request must not be modified
+	req = req.Clone(req.Context())
+	for key, values := range rt.header {
+		req.Header[key] = values
+	}
+
+	return rt.next.RoundTrip(req)
+}
+
+// loadQuorumClientTLSConfig creates the TLS configuration of a quorum client.
+// Returns nil if no custom TLS configuration is needed.
+func loadQuorumClientTLSConfig(config *QuorumClientConfig) (*tls.Config, error) {
+	if config.TLSConfig == nil && config.CAFile == "" && config.CertFile == "" && config.KeyFile == "" {
+		//nolint:nilnil // nil signals that the default TLS configuration is used
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.TLSConfig != nil {
+		tlsConfig = config.TLSConfig.Clone()
+	}
+
+	if config.CAFile != "" {
+		caCert, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load CA file of quorum client %s: %w", config.BaseURL, err)
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("CA file of quorum client %s contains no valid certificates: %s", config.BaseURL, config.CAFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, fmt.Errorf("quorum client %s needs both a certificate and a key file for client authentication", config.BaseURL)
+		}
+
+		clientCert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate of quorum client %s: %w", config.BaseURL, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,61 @@
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pollHealth calls the info API of every client in the quorum in parallel and updates its statistics.
+// The circuit breakers and the concurrency limit of the quorum checks are not affected.
+func (q *quorum) pollHealth(ctx context.Context) {
+	wg := &sync.WaitGroup{}
+
+	for _, quorumGroupEntries := range q.Groups {
+		for _, entry := range quorumGroupEntries {
+			wg.Add(1)
+
+			go func(entry *quorumGroupEntry) {
+				defer wg.Done()
+
+				requestCtx, requestCancel := context.WithTimeout(ctx, entry.timeout)
+				defer requestCancel()
+
+				ts := time.Now()
+
+				_, err := entry.api.Info(requestCtx)
+				if err != nil && ctx.Err() != nil {
+					// the poll was aborted, the failed request is not the fault of the client
+					return
+				}
+
+				q.quorumStatsLock.Lock()
+				entry.stats.ResponseTimeSeconds = time.Since(ts).Seconds()
+				entry.stats.Error = err
+				q.quorumStatsLock.Unlock()
+			}(entry)
+		}
+	}
+
+	wg.Wait()
+}
+
+// runQuorumHealthPoll polls the health of the clients in the current quorum every interval until the coordinator is shut down,
+// so the statistics of the clients show their liveness between milestones.
+func (coo *Coordinator) runQuorumHealthPoll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-coo.shutdownCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the quorum could be replaced at runtime, every poll uses the current one
+		if q := coo.currentQuorum(); q != nil {
+			q.pollHealth(coo.shutdownCtx)
+		}
+	}
+}
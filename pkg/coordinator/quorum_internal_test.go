@@ -0,0 +1,1117 @@
+package coordinator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/nodeclient"
+)
+
+// newWhiteFlagTestServer creates a test server answering white flag requests with empty merkle roots.
+// Every received request is passed to onRequest.
+func newWhiteFlagTestServer(t *testing.T, onRequest func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	return newWhiteFlagTestServerWithRoots(t, &MilestoneMerkleRoots{}, onRequest)
+}
+
+// newWhiteFlagTestServerWithRoots creates a test server answering white flag requests with the given merkle roots.
+// Every received request is passed to onRequest.
+func newWhiteFlagTestServerWithRoots(t *testing.T, merkleRoots *MilestoneMerkleRoots, onRequest func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if onRequest != nil {
+			onRequest(r)
+		}
+
+		w.Header().Set("Content-Type", nodeclient.MIMEApplicationJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(&nodeclient.ComputeWhiteFlagMutationsResponseInternal{
+			InclusionMerkleRoot: iotago.EncodeHex(merkleRoots.InclusionMerkleRoot[:]),
+			AppliedMerkleRoot:   iotago.EncodeHex(merkleRoots.AppliedMerkleRoot[:]),
+		}))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// computeWhiteFlagOfFirstEntry calls the white flag API of the first client in the given group.
+func computeWhiteFlagOfFirstEntry(t *testing.T, q *quorum, groupName string) {
+	t.Helper()
+
+	_, err := q.Groups[groupName][0].api.ComputeWhiteFlagMutations(context.Background(), 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{})
+	require.NoError(t, err)
+}
+
+func TestQuorumClientBearerToken(t *testing.T) {
+	var authHeader string
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL, Token: "secret-token"}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	computeWhiteFlagOfFirstEntry(t, q, "group")
+	require.Equal(t, "Bearer secret-token", authHeader)
+}
+
+func TestQuorumClientBearerTokenPrecedence(t *testing.T) {
+	var authHeader string
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL, Username: "user", Password: "pass", Token: "secret-token"}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	computeWhiteFlagOfFirstEntry(t, q, "group")
+	require.Equal(t, "Bearer secret-token", authHeader)
+}
+
+func TestQuorumClientBasicAuth(t *testing.T) {
+	var username, password string
+	var ok bool
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		username, password, ok = r.BasicAuth()
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL, Username: "user", Password: "pass"}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	computeWhiteFlagOfFirstEntry(t, q, "group")
+	require.True(t, ok)
+	require.Equal(t, "user", username)
+	require.Equal(t, "pass", password)
+}
+
+func TestQuorumCorrelationID(t *testing.T) {
+	var correlationIDs []string
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		correlationIDs = append(correlationIDs, r.Header.Get(QuorumCorrelationIDHeader))
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	checkQuorum := func() {
+		require.NoError(t, q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil))
+	}
+
+	checkQuorum()
+	require.Len(t, correlationIDs, 1)
+	require.NotEmpty(t, correlationIDs[0])
+
+	// the statistics contain the correlation ID of the last request
+	stats := q.quorumStatsSnapshot()
+	require.Len(t, stats, 1)
+	require.Equal(t, correlationIDs[0], stats[0].CorrelationID)
+
+	// every request gets a new correlation ID
+	checkQuorum()
+	require.Len(t, correlationIDs, 2)
+	require.NotEqual(t, correlationIDs[0], correlationIDs[1])
+}
+
+func TestQuorumCorrelationIDOnError(t *testing.T) {
+	var correlationID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		correlationID = r.Header.Get(QuorumCorrelationIDHeader)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	var errorCorrelationID string
+	err = q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, func(_ string, _ *quorumGroupEntry, correlationID string, _ error) {
+		errorCorrelationID = correlationID
+	})
+	require.ErrorIs(t, err, ErrQuorumGroupNoAnswer)
+
+	// the error is reported with the correlation ID the client received
+	require.NotEmpty(t, correlationID)
+	require.Equal(t, correlationID, errorCorrelationID)
+	require.Equal(t, correlationID, q.quorumStatsSnapshot()[0].CorrelationID)
+}
+
+func TestQuorumStatsDuringSlowCheck(t *testing.T) {
+	requestReceived := make(chan struct{}, 1)
+	releaseRequest := make(chan struct{})
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		requestReceived <- struct{}{}
+		<-releaseRequest
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, 5*time.Second, nil)
+	require.NoError(t, err)
+
+	checkDone := make(chan error, 1)
+	go func() {
+		checkDone <- q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	}()
+
+	// wait until the quorum check is in flight
+	<-requestReceived
+
+	statsReturned := make(chan []QuorumClientStatistic, 1)
+	go func() {
+		statsReturned <- q.quorumStatsSnapshot()
+	}()
+
+	select {
+	case stats := <-statsReturned:
+		require.Len(t, stats, 1)
+	case <-time.After(time.Second):
+		require.FailNow(t, "quorum stats blocked by the in-flight quorum check")
+	}
+
+	close(releaseRequest)
+	require.NoError(t, <-checkDone)
+
+	stats := q.quorumStatsSnapshot()
+	require.Len(t, stats, 1)
+	require.NoError(t, stats[0].Error)
+}
+
+func TestQuorumMismatchError(t *testing.T) {
+	server := newWhiteFlagTestServer(t, nil)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{Alias: "node", BaseURL: server.URL}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	cooMerkleRoots := &MilestoneMerkleRoots{AppliedMerkleRoot: iotago.MilestoneMerkleProof{1}}
+
+	err = q.checkMerkleTreeHash(cooMerkleRoots, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	require.ErrorIs(t, err, ErrQuorumMerkleTreeHashMismatch)
+
+	var mismatchErr *QuorumMismatchError
+	require.ErrorAs(t, err, &mismatchErr)
+	require.Equal(t, "group", mismatchErr.Group)
+	require.Equal(t, "node", mismatchErr.Alias)
+	require.Equal(t, server.URL, mismatchErr.BaseURL)
+	require.Equal(t, *cooMerkleRoots, mismatchErr.CooMerkleRoots)
+	require.Equal(t, MilestoneMerkleRoots{}, mismatchErr.NodeMerkleRoots)
+}
+
+func TestQuorumCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+	q.setCircuitBreaker(2, time.Hour)
+
+	checkQuorum := func() error {
+		return q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	}
+
+	require.ErrorIs(t, checkQuorum(), ErrQuorumGroupNoAnswer)
+	require.Equal(t, CircuitBreakerClosed, q.quorumStatsSnapshot()[0].CircuitBreakerState)
+
+	require.ErrorIs(t, checkQuorum(), ErrQuorumGroupNoAnswer)
+	require.Equal(t, CircuitBreakerOpen, q.quorumStatsSnapshot()[0].CircuitBreakerState)
+	require.EqualValues(t, 2, requests.Load())
+
+	// the client is skipped while the circuit breaker is open
+	require.ErrorIs(t, checkQuorum(), ErrQuorumGroupNoAnswer)
+	require.EqualValues(t, 2, requests.Load())
+
+	// after the cooldown a single probe is sent
+	q.setCircuitBreaker(2, 0)
+	require.ErrorIs(t, checkQuorum(), ErrQuorumGroupNoAnswer)
+	require.EqualValues(t, 3, requests.Load())
+	require.Equal(t, CircuitBreakerOpen, q.quorumStatsSnapshot()[0].CircuitBreakerState)
+}
+
+func TestQuorumWeightThreshold(t *testing.T) {
+	trustedServer := newWhiteFlagTestServer(t, nil)
+	otherServer := newWhiteFlagTestServer(t, nil)
+	mismatchingServer := newWhiteFlagTestServerWithRoots(t, &MilestoneMerkleRoots{AppliedMerkleRoot: iotago.MilestoneMerkleProof{1}}, nil)
+
+	checkQuorum := func(weightThreshold int, clients ...*QuorumClientConfig) error {
+		q, err := newQuorum(map[string][]*QuorumClientConfig{"group": clients}, time.Second, nil)
+		require.NoError(t, err)
+		q.setWeightThreshold(weightThreshold)
+
+		return q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	}
+
+	// without a threshold every mismatch is critical
+	require.ErrorIs(t, checkQuorum(0,
+		&QuorumClientConfig{BaseURL: trustedServer.URL, Weight: 3},
+		&QuorumClientConfig{BaseURL: mismatchingServer.URL},
+	), ErrQuorumMerkleTreeHashMismatch)
+
+	// the agreeing weight outweighs the mismatching client
+	require.NoError(t, checkQuorum(3,
+		&QuorumClientConfig{BaseURL: trustedServer.URL, Weight: 3},
+		&QuorumClientConfig{BaseURL: mismatchingServer.URL},
+	))
+
+	// the weights default to 1
+	require.NoError(t, checkQuorum(2,
+		&QuorumClientConfig{BaseURL: trustedServer.URL},
+		&QuorumClientConfig{BaseURL: otherServer.URL},
+		&QuorumClientConfig{BaseURL: mismatchingServer.URL},
+	))
+
+	// the agreeing weight doesn't reach the threshold and a client mismatched
+	require.ErrorIs(t, checkQuorum(3,
+		&QuorumClientConfig{BaseURL: trustedServer.URL},
+		&QuorumClientConfig{BaseURL: otherServer.URL},
+		&QuorumClientConfig{BaseURL: mismatchingServer.URL, Weight: 5},
+	), ErrQuorumMerkleTreeHashMismatch)
+
+	// the agreeing weight doesn't reach the threshold without a mismatch
+	err := checkQuorum(3,
+		&QuorumClientConfig{BaseURL: trustedServer.URL},
+		&QuorumClientConfig{BaseURL: otherServer.URL},
+	)
+	require.ErrorIs(t, err, ErrQuorumWeightThresholdNotReached)
+	require.Error(t, common.IsSoftError(err))
+}
+
+func TestQuorumWeightThresholdUnreachable(t *testing.T) {
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group":      {{BaseURL: "http://node1:14265", Weight: 2}, {BaseURL: "http://node2:14265"}},
+		"otherGroup": {{BaseURL: "http://node3:14265", Weight: 5}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	q.setWeightThreshold(3)
+	require.NoError(t, q.validateWeightThreshold())
+
+	q.setWeightThreshold(4)
+	err = q.validateWeightThreshold()
+	require.ErrorIs(t, err, ErrQuorumWeightThresholdUnreachable)
+	require.Contains(t, err.Error(), "group: group,")
+}
+
+func TestQuorumWeightThresholdReportsMismatch(t *testing.T) {
+	var mismatchAnswered atomic.Bool
+	mismatchingServer := newWhiteFlagTestServerWithRoots(t, &MilestoneMerkleRoots{AppliedMerkleRoot: iotago.MilestoneMerkleProof{1}}, func(r *http.Request) {
+		mismatchAnswered.Store(true)
+	})
+	trustedServer := newWhiteFlagTestServer(t, func(r *http.Request) {
+		// answer after the mismatching client, so the mismatch is seen before the threshold is reached
+		for !mismatchAnswered.Load() {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: trustedServer.URL, Weight: 2}, {Alias: "mismatching", BaseURL: mismatchingServer.URL}},
+	}, 5*time.Second, nil)
+	require.NoError(t, err)
+	q.setWeightThreshold(2)
+
+	var reportedErrs []error
+	require.NoError(t, q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, func(groupName string, entry *quorumGroupEntry, correlationID string, err error) {
+		require.Equal(t, "group", groupName)
+		require.Equal(t, "mismatching", entry.stats.Alias)
+		require.NotEmpty(t, correlationID)
+		reportedErrs = append(reportedErrs, err)
+	}))
+
+	require.Len(t, reportedErrs, 1)
+	require.ErrorIs(t, reportedErrs[0], ErrQuorumMerkleTreeHashMismatch)
+}
+
+func TestQuorumClientTimeout(t *testing.T) {
+	fastServer := newWhiteFlagTestServer(t, nil)
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body needs to be consumed to detect the closed connection
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		// block until the request is cancelled
+		<-r.Context().Done()
+	}))
+	t.Cleanup(slowServer.Close)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: fastServer.URL, Alias: "fast"}, {BaseURL: slowServer.URL, Alias: "slow", Timeout: 100 * time.Millisecond}},
+	}, 30*time.Second, nil)
+	require.NoError(t, err)
+
+	var reportedAliases []string
+	var reportedErrs []error
+	checkStart := time.Now()
+	require.NoError(t, q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, func(_ string, entry *quorumGroupEntry, _ string, err error) {
+		reportedAliases = append(reportedAliases, entry.stats.Alias)
+		reportedErrs = append(reportedErrs, err)
+	}))
+
+	// the slow client timed out after its own timeout instead of the quorum timeout
+	require.Less(t, time.Since(checkStart), 5*time.Second)
+	require.Equal(t, []string{"slow"}, reportedAliases)
+	require.ErrorIs(t, reportedErrs[0], context.DeadlineExceeded)
+
+	for _, stats := range q.quorumStatsSnapshot() {
+		if stats.Alias == "slow" {
+			require.Error(t, stats.Error)
+
+			continue
+		}
+		require.NoError(t, stats.Error)
+	}
+}
+
+func TestQuorumWeightThresholdDoesNotPenalizeSlowClients(t *testing.T) {
+	fastServer := newWhiteFlagTestServer(t, nil)
+
+	slowRequestCancelled := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body needs to be consumed to detect the closed connection
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		// block until the request is cancelled
+		<-r.Context().Done()
+		close(slowRequestCancelled)
+	}))
+	t.Cleanup(slowServer.Close)
+
+	// the other group keeps the quorum running until the cancelled request of the slow client returned
+	otherGroupServer := newWhiteFlagTestServer(t, func(r *http.Request) {
+		select {
+		case <-slowRequestCancelled:
+			time.Sleep(100 * time.Millisecond)
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group":      {{BaseURL: fastServer.URL}, {BaseURL: slowServer.URL}},
+		"otherGroup": {{BaseURL: otherGroupServer.URL}},
+	}, 30*time.Second, nil)
+	require.NoError(t, err)
+	q.setWeightThreshold(1)
+	q.setCircuitBreaker(1, time.Hour)
+
+	var reportedErrs atomic.Int32
+	require.NoError(t, q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, func(_ string, _ *quorumGroupEntry, _ string, _ error) {
+		reportedErrs.Add(1)
+	}))
+
+	select {
+	case <-slowRequestCancelled:
+	default:
+		require.FailNow(t, "request of the slow client was not cancelled")
+	}
+
+	require.Zero(t, reportedErrs.Load())
+	for _, stats := range q.quorumStatsSnapshot() {
+		require.NoError(t, stats.Error)
+		require.Equal(t, CircuitBreakerClosed, stats.CircuitBreakerState)
+	}
+}
+
+func TestQuorumAbortsGroupsOnMismatch(t *testing.T) {
+	mismatchingServer := newWhiteFlagTestServerWithRoots(t, &MilestoneMerkleRoots{AppliedMerkleRoot: iotago.MilestoneMerkleProof{1}}, nil)
+
+	slowRequestCancelled := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body needs to be consumed to detect the closed connection
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		// block until the request is cancelled
+		<-r.Context().Done()
+		close(slowRequestCancelled)
+	}))
+	t.Cleanup(slowServer.Close)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"mismatching": {{BaseURL: mismatchingServer.URL}},
+		"slow":        {{BaseURL: slowServer.URL}},
+	}, 30*time.Second, nil)
+	require.NoError(t, err)
+
+	ts := time.Now()
+	err = q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	require.ErrorIs(t, err, ErrQuorumMerkleTreeHashMismatch)
+	require.Less(t, time.Since(ts), 5*time.Second)
+
+	select {
+	case <-slowRequestCancelled:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "request of the slow group was not cancelled")
+	}
+}
+
+func TestQuorumNoGoroutineLeakOnMultipleErrors(t *testing.T) {
+	quorumGroups := make(map[string][]*QuorumClientConfig)
+	for i := 0; i < 5; i++ {
+		quorumGroups[fmt.Sprintf("group%d", i)] = []*QuorumClientConfig{{BaseURL: "http://localhost"}}
+	}
+
+	q, err := newQuorum(quorumGroups, time.Second, nil)
+	require.NoError(t, err)
+
+	// open the circuit breakers of all clients, so all groups fail at the same time without any request
+	q.setCircuitBreaker(1, time.Hour)
+	for _, quorumGroup := range q.Groups {
+		quorumGroup[0].breaker.recordResult(time.Now(), ErrQuorumGroupNoAnswer)
+	}
+
+	goroutinesBefore := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		err := q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+		require.ErrorIs(t, err, ErrQuorumGroupNoAnswer)
+	}
+
+	// require.Eventually can't be used, it runs the condition in a separate goroutine
+	goroutinesAfter := 0
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if goroutinesAfter = runtime.NumGoroutine(); goroutinesAfter <= goroutinesBefore {
+			break
+		}
+	}
+	require.LessOrEqual(t, goroutinesAfter, goroutinesBefore)
+}
+
+func TestQuorumConcurrencyLimit(t *testing.T) {
+	var requestsInFlight, maxRequestsInFlight atomic.Int32
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		inFlight := requestsInFlight.Add(1)
+		defer requestsInFlight.Add(-1)
+
+		for {
+			maxInFlight := maxRequestsInFlight.Load()
+			if inFlight <= maxInFlight || maxRequestsInFlight.CompareAndSwap(maxInFlight, inFlight) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	})
+
+	quorumGroups := make(map[string][]*QuorumClientConfig)
+	for i := 0; i < 3; i++ {
+		quorumGroups[fmt.Sprintf("group%d", i)] = []*QuorumClientConfig{{BaseURL: server.URL}, {BaseURL: server.URL}}
+	}
+
+	q, err := newQuorum(quorumGroups, 5*time.Second, nil)
+	require.NoError(t, err)
+	q.setConcurrencyLimit(2)
+
+	require.NoError(t, q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil))
+	require.EqualValues(t, 2, maxRequestsInFlight.Load())
+}
+
+func TestQuorumConcurrencyLimitTimeout(t *testing.T) {
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		time.Sleep(time.Second)
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group1": {{BaseURL: server.URL}},
+		"group2": {{BaseURL: server.URL}},
+	}, 100*time.Millisecond, nil)
+	require.NoError(t, err)
+	q.setConcurrencyLimit(1)
+
+	// waiting for a free slot doesn't extend the quorum timeout
+	ts := time.Now()
+	err = q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	require.ErrorIs(t, err, ErrQuorumGroupNoAnswer)
+	require.Less(t, time.Since(ts), 500*time.Millisecond)
+}
+
+func TestQuorumResultCache(t *testing.T) {
+	var requests atomic.Int32
+	var failRequests atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failRequests.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		emptyRoot := iotago.EncodeHex(make([]byte, iotago.MilestoneMerkleProofLength))
+		w.Header().Set("Content-Type", nodeclient.MIMEApplicationJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(&nodeclient.ComputeWhiteFlagMutationsResponseInternal{
+			InclusionMerkleRoot: emptyRoot,
+			AppliedMerkleRoot:   emptyRoot,
+		}))
+	}))
+	t.Cleanup(server.Close)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+	q.setResultCache(10, 200*time.Millisecond)
+
+	parents := iotago.BlockIDs{iotago.EmptyBlockID()}
+	checkQuorum := func(index iotago.MilestoneIndex, parents iotago.BlockIDs) error {
+		return q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, index, 1, parents, iotago.MilestoneID{}, nil)
+	}
+
+	require.NoError(t, checkQuorum(1, parents))
+	require.EqualValues(t, 1, requests.Load())
+
+	// the same inputs are served from the cache
+	require.NoError(t, checkQuorum(1, parents))
+	require.EqualValues(t, 1, requests.Load())
+
+	// different inputs ask the quorum again
+	require.NoError(t, checkQuorum(1, iotago.BlockIDs{iotago.BlockID{1}}))
+	require.EqualValues(t, 2, requests.Load())
+
+	// the cache is invalidated if the index advances
+	require.NoError(t, checkQuorum(2, parents))
+	require.EqualValues(t, 3, requests.Load())
+	require.NoError(t, checkQuorum(1, parents))
+	require.EqualValues(t, 4, requests.Load())
+
+	// failed checks are not cached
+	failRequests.Store(true)
+	require.ErrorIs(t, checkQuorum(3, parents), ErrQuorumGroupNoAnswer)
+	require.ErrorIs(t, checkQuorum(3, parents), ErrQuorumGroupNoAnswer)
+	require.EqualValues(t, 6, requests.Load())
+
+	// cached checks expire
+	failRequests.Store(false)
+	require.NoError(t, checkQuorum(3, parents))
+	require.NoError(t, checkQuorum(3, parents))
+	require.EqualValues(t, 7, requests.Load())
+
+	time.Sleep(250 * time.Millisecond)
+	require.NoError(t, checkQuorum(3, parents))
+	require.EqualValues(t, 8, requests.Load())
+}
+
+func TestQuorumTransport(t *testing.T) {
+	var newConnections atomic.Int32
+	var slowRequests atomic.Bool
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slowRequests.Load() {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		emptyRoot := iotago.EncodeHex(make([]byte, iotago.MilestoneMerkleProofLength))
+		w.Header().Set("Content-Type", nodeclient.MIMEApplicationJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(&nodeclient.ComputeWhiteFlagMutationsResponseInternal{
+			InclusionMerkleRoot: emptyRoot,
+			AppliedMerkleRoot:   emptyRoot,
+		}))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConnections.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	transport := &http.Transport{MaxIdleConnsPerHost: 10, IdleConnTimeout: time.Minute}
+	t.Cleanup(transport.CloseIdleConnections)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{Alias: "default", BaseURL: server.URL}, {Alias: "short timeout", BaseURL: server.URL, Timeout: 50 * time.Millisecond}},
+	}, time.Second, transport)
+	require.NoError(t, err)
+
+	checkQuorum := func() error {
+		return q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	}
+
+	// the connections are reused by the following checks
+	require.NoError(t, checkQuorum())
+	connectionsAfterFirstCheck := newConnections.Load()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, checkQuorum())
+	}
+	require.Equal(t, connectionsAfterFirstCheck, newConnections.Load())
+
+	// the timeout of the client still applies on top of the shared transport
+	slowRequests.Store(true)
+	require.NoError(t, checkQuorum())
+
+	stats := q.quorumStatsSnapshot()
+	require.Len(t, stats, 2)
+	for _, stat := range stats {
+		if stat.Alias == "short timeout" {
+			require.Error(t, stat.Error)
+		} else {
+			require.NoError(t, stat.Error)
+		}
+	}
+}
+
+func TestQuorumClientUnixSocket(t *testing.T) {
+	// unix socket paths are limited in length, so the test directory can't be used
+	socketDir, err := os.MkdirTemp("", "quorum")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(socketDir) })
+
+	socketPath := filepath.Join(socketDir, "node.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	var requestPath string
+	server := newWhiteFlagTestServer(t, func(r *http.Request) {
+		requestPath = r.URL.Path
+	})
+	// serve the same handler via the unix socket
+	unixServer := &httptest.Server{Listener: listener, Config: &http.Server{Handler: server.Config.Handler, ReadHeaderTimeout: time.Second}}
+	unixServer.Start()
+	t.Cleanup(unixServer.Close)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: "unix://" + socketPath}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil))
+	require.Equal(t, "/api/core/v2/whiteflag", requestPath)
+	require.Equal(t, "unix://"+socketPath, q.quorumStatsSnapshot()[0].BaseURL)
+
+	for _, baseURL := range []string{"unix://", "unix://host/node.sock"} {
+		_, err = newQuorum(map[string][]*QuorumClientConfig{
+			"group": {{BaseURL: baseURL}},
+		}, time.Second, nil)
+		require.ErrorContains(t, err, "unix:///path/to/socket")
+	}
+}
+
+// writeTestPEMFile PEM encodes the given block into a file in the test directory and returns its path.
+func writeTestPEMFile(t *testing.T, name string, blockType string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600))
+
+	return path
+}
+
+// newTLSWhiteFlagTestServer creates a TLS test server answering white flag requests with empty merkle roots,
+// and returns it together with the path of a CA file containing its certificate.
+// If clientCert is given, the server requires this certificate for client authentication.
+func newTLSWhiteFlagTestServer(t *testing.T, clientCert *x509.Certificate) (*httptest.Server, string) {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(newWhiteFlagTestServer(t, nil).Config.Handler)
+	if clientCert != nil {
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCert)
+		server.TLS = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server, writeTestPEMFile(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+}
+
+// newTestClientCertificate creates a self-signed client certificate and returns it together with
+// the paths of the PEM encoded certificate and key files.
+func newTestClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "coordinator"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return cert, writeTestPEMFile(t, "client.pem", "CERTIFICATE", certBytes), writeTestPEMFile(t, "client.key", "EC PRIVATE KEY", keyBytes)
+}
+
+func TestQuorumClientTLSCAFile(t *testing.T) {
+	server, caFile := newTLSWhiteFlagTestServer(t, nil)
+
+	// the certificate of the server is not trusted by default
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	_, err = q.Groups["group"][0].api.ComputeWhiteFlagMutations(context.Background(), 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{})
+	require.Error(t, err)
+
+	q, err = newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL, CAFile: caFile}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	computeWhiteFlagOfFirstEntry(t, q, "group")
+}
+
+func TestQuorumClientMutualTLS(t *testing.T) {
+	clientCert, certFile, keyFile := newTestClientCertificate(t)
+	server, caFile := newTLSWhiteFlagTestServer(t, clientCert)
+
+	// the server rejects clients without a certificate
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL, CAFile: caFile}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	_, err = q.Groups["group"][0].api.ComputeWhiteFlagMutations(context.Background(), 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{})
+	require.Error(t, err)
+
+	q, err = newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL, CAFile: caFile, CertFile: certFile, KeyFile: keyFile}},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	computeWhiteFlagOfFirstEntry(t, q, "group")
+}
+
+func TestQuorumClientTLSConfigErrors(t *testing.T) {
+	_, certFile, keyFile := newTestClientCertificate(t)
+	missingFile := filepath.Join(t.TempDir(), "missing.pem")
+	invalidFile := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("no PEM data"), 0600))
+
+	tests := []struct {
+		name          string
+		config        *QuorumClientConfig
+		expectedError string
+	}{
+		{name: "missing CA file", config: &QuorumClientConfig{CAFile: missingFile}, expectedError: "unable to load CA file"},
+		{name: "invalid CA file", config: &QuorumClientConfig{CAFile: invalidFile}, expectedError: "contains no valid certificates"},
+		{name: "certificate without key", config: &QuorumClientConfig{CertFile: certFile}, expectedError: "needs both a certificate and a key file"},
+		{name: "key without certificate", config: &QuorumClientConfig{KeyFile: keyFile}, expectedError: "needs both a certificate and a key file"},
+		{name: "missing certificate file", config: &QuorumClientConfig{CertFile: missingFile, KeyFile: keyFile}, expectedError: "unable to load client certificate"},
+		{name: "invalid certificate file", config: &QuorumClientConfig{CertFile: invalidFile, KeyFile: keyFile}, expectedError: "unable to load client certificate"},
+		{name: "swapped certificate and key files", config: &QuorumClientConfig{CertFile: keyFile, KeyFile: certFile}, expectedError: "unable to load client certificate"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.BaseURL = "https://localhost"
+
+			_, err := newQuorum(map[string][]*QuorumClientConfig{
+				"group": {test.config},
+			}, time.Second, nil)
+			require.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestQuorumFailClosed(t *testing.T) {
+	server := newWhiteFlagTestServer(t, nil)
+
+	// newFailingQuorum creates a quorum with the given amount of groups that don't answer and a group that answers.
+	newFailingQuorum := func(failingGroups int, answeringGroup bool, failClosed bool) *quorum {
+		quorumGroups := make(map[string][]*QuorumClientConfig)
+		for i := 0; i < failingGroups; i++ {
+			quorumGroups[fmt.Sprintf("failing%d", i)] = []*QuorumClientConfig{{BaseURL: fmt.Sprintf("http://failing%d", i)}}
+		}
+		if answeringGroup {
+			quorumGroups["answering"] = []*QuorumClientConfig{{BaseURL: server.URL}}
+		}
+
+		q, err := newQuorum(quorumGroups, time.Second, nil)
+		require.NoError(t, err)
+		q.setFailClosed(failClosed)
+
+		// open the circuit breakers of the failing clients, so they fail without any request
+		q.setCircuitBreaker(1, time.Hour)
+		for i := 0; i < failingGroups; i++ {
+			q.Groups[fmt.Sprintf("failing%d", i)][0].breaker.recordResult(time.Now(), ErrQuorumGroupNoAnswer)
+		}
+
+		return q
+	}
+
+	checkQuorum := func(q *quorum) error {
+		return q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, 1, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, nil)
+	}
+
+	// the quorum fails open by default
+	err := checkQuorum(newFailingQuorum(3, false, false))
+	require.ErrorIs(t, err, ErrQuorumGroupNoAnswer)
+	require.NotNil(t, common.IsSoftError(err))
+
+	// no group answered
+	err = checkQuorum(newFailingQuorum(3, false, true))
+	require.ErrorIs(t, err, ErrQuorumNoAnswer)
+	require.NotNil(t, common.IsCriticalError(err))
+
+	// a single group without answers is still not critical
+	err = checkQuorum(newFailingQuorum(2, true, true))
+	require.ErrorIs(t, err, ErrQuorumGroupNoAnswer)
+	require.NotNil(t, common.IsSoftError(err))
+
+	// the error of a group is not lost if all groups finished at the same time
+	q := newFailingQuorum(1, false, false)
+	for i := 0; i < 100; i++ {
+		require.ErrorIs(t, checkQuorum(q), ErrQuorumGroupNoAnswer)
+	}
+}
+
+func TestQuorumClockSkew(t *testing.T) {
+	var nodeClockOffset atomic.Int64
+	var infoRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", nodeclient.MIMEApplicationJSON)
+
+		if r.URL.Path == nodeclient.RouteInfo {
+			infoRequests.Add(1)
+			w.Header().Set("Date", time.Now().Add(time.Duration(nodeClockOffset.Load())).UTC().Format(http.TimeFormat))
+			_, _ = w.Write([]byte("{}"))
+
+			return
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(&nodeclient.ComputeWhiteFlagMutationsResponseInternal{
+			InclusionMerkleRoot: iotago.EncodeHex(make([]byte, iotago.MilestoneMerkleProofLength)),
+			AppliedMerkleRoot:   iotago.EncodeHex(make([]byte, iotago.MilestoneMerkleProofLength)),
+		}))
+	}))
+	t.Cleanup(server.Close)
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, 5*time.Second, nil)
+	require.NoError(t, err)
+
+	checkQuorum := func() []error {
+		var entryErrors []error
+		err := q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 1, uint32(time.Now().Unix()), iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneID{}, func(_ string, _ *quorumGroupEntry, _ string, err error) {
+			entryErrors = append(entryErrors, err)
+		})
+		// the clock skew doesn't fail the quorum
+		require.NoError(t, err)
+
+		return entryErrors
+	}
+
+	// the clock skew check is disabled by default
+	nodeClockOffset.Store(int64(-time.Minute))
+	require.Empty(t, checkQuorum())
+	require.Zero(t, infoRequests.Load())
+
+	// the coordinator is ahead of the node
+	q.setClockSkewThreshold(10 * time.Second)
+	entryErrors := checkQuorum()
+	require.Len(t, entryErrors, 1)
+	require.ErrorIs(t, entryErrors[0], ErrQuorumClockSkew)
+	clockSkew := q.quorumStatsSnapshot()[0].ClockSkew
+	require.InDelta(t, time.Minute, clockSkew, float64(3*time.Second))
+
+	// the clocks are in sync
+	nodeClockOffset.Store(0)
+	require.Empty(t, checkQuorum())
+	clockSkew = q.quorumStatsSnapshot()[0].ClockSkew
+	require.InDelta(t, 0, clockSkew, float64(3*time.Second))
+}
+
+// quorumTraffic counts the bytes of the bodies sent to and received from a quorum test server.
+type quorumTraffic struct {
+	requestBytes  atomic.Int64
+	responseBytes atomic.Int64
+}
+
+// countingResponseWriter counts the bytes written to the response body.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	written *atomic.Int64
+}
+
+func (w *countingResponseWriter) Write(data []byte) (int, error) {
+	w.written.Add(int64(len(data)))
+
+	return w.ResponseWriter.Write(data)
+}
+
+// newCompressingWhiteFlagTestServer creates a test server answering white flag requests with the given merkle roots,
+// which accepts gzip compressed requests and compresses its responses if the client asks for it.
+// Every decoded white flag request is passed to onRequest.
+func newCompressingWhiteFlagTestServer(t testing.TB, merkleRoots *MilestoneMerkleRoots, traffic *quorumTraffic, onRequest func(r *http.Request, request *nodeclient.ComputeWhiteFlagMutationsRequest)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		traffic.requestBytes.Add(int64(len(body)))
+
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+			require.NoError(t, err)
+			body, err = io.ReadAll(gzipReader)
+			require.NoError(t, err)
+		}
+
+		request := &nodeclient.ComputeWhiteFlagMutationsRequest{}
+		require.NoError(t, json.Unmarshal(body, request))
+		if onRequest != nil {
+			onRequest(r, request)
+		}
+
+		var responseWriter io.Writer = &countingResponseWriter{ResponseWriter: w, written: &traffic.responseBytes}
+		w.Header().Set("Content-Type", nodeclient.MIMEApplicationJSON)
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gzipWriter := gzip.NewWriter(responseWriter)
+			defer func() { require.NoError(t, gzipWriter.Close()) }()
+			responseWriter = gzipWriter
+		}
+
+		require.NoError(t, json.NewEncoder(responseWriter).Encode(&nodeclient.ComputeWhiteFlagMutationsResponseInternal{
+			InclusionMerkleRoot: iotago.EncodeHex(merkleRoots.InclusionMerkleRoot[:]),
+			AppliedMerkleRoot:   iotago.EncodeHex(merkleRoots.AppliedMerkleRoot[:]),
+		}))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestQuorumCompression(t *testing.T) {
+	merkleRoots := &MilestoneMerkleRoots{}
+	_, err := rand.Read(merkleRoots.InclusionMerkleRoot[:])
+	require.NoError(t, err)
+	_, err = rand.Read(merkleRoots.AppliedMerkleRoot[:])
+	require.NoError(t, err)
+
+	parents := make(iotago.BlockIDs, 8)
+	for i := range parents {
+		_, err := rand.Read(parents[i][:])
+		require.NoError(t, err)
+	}
+
+	var contentEncoding string
+	var receivedParents []string
+	traffic := &quorumTraffic{}
+	server := newCompressingWhiteFlagTestServer(t, merkleRoots, traffic, func(r *http.Request, request *nodeclient.ComputeWhiteFlagMutationsRequest) {
+		contentEncoding = r.Header.Get("Content-Encoding")
+		receivedParents = request.Parents
+	})
+
+	q, err := newQuorum(map[string][]*QuorumClientConfig{
+		"group": {{BaseURL: server.URL}},
+	}, 5*time.Second, nil)
+	require.NoError(t, err)
+
+	expectedParents := make([]string, len(parents))
+	for i, parent := range parents {
+		expectedParents[i] = parent.ToHex()
+	}
+
+	// requests are not compressed by default
+	require.NoError(t, q.checkMerkleTreeHash(merkleRoots, 1, 1, parents, iotago.MilestoneID{}, nil))
+	require.Empty(t, contentEncoding)
+	require.Equal(t, expectedParents, receivedParents)
+	uncompressedRequestBytes := traffic.requestBytes.Load()
+
+	// the compressed request and response round-trip
+	q.setCompression(true)
+	require.NoError(t, q.checkMerkleTreeHash(merkleRoots, 2, 2, parents, iotago.MilestoneID{}, nil))
+	require.Equal(t, "gzip", contentEncoding)
+	require.Equal(t, expectedParents, receivedParents)
+	require.Less(t, traffic.requestBytes.Load()-uncompressedRequestBytes, uncompressedRequestBytes)
+
+	// a mismatch is still detected in the decompressed response
+	require.ErrorIs(t, q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, 3, 3, parents, iotago.MilestoneID{}, nil), ErrQuorumMerkleTreeHashMismatch)
+}
+
+func BenchmarkQuorumCompression(b *testing.B) {
+	parents := make(iotago.BlockIDs, iotago.BlockMaxParents)
+	for i := range parents {
+		if _, err := rand.Read(parents[i][:]); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, compression := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compression %t", compression), func(b *testing.B) {
+			traffic := &quorumTraffic{}
+			server := newCompressingWhiteFlagTestServer(b, &MilestoneMerkleRoots{}, traffic, nil)
+
+			q, err := newQuorum(map[string][]*QuorumClientConfig{
+				"group": {{BaseURL: server.URL}},
+			}, 5*time.Second, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			q.setCompression(compression)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if err := q.checkMerkleTreeHash(&MilestoneMerkleRoots{}, iotago.MilestoneIndex(i+1), 1, parents, iotago.MilestoneID{}, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			// the bytes of the bodies on the wire
+			b.ReportMetric(float64(traffic.requestBytes.Load())/float64(b.N), "request-bytes/op")
+			b.ReportMetric(float64(traffic.responseBytes.Load())/float64(b.N), "response-bytes/op")
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// MilestoneExistsFunc returns the info of the milestone with the given index if the connected node knows it.
+// Returns nil if the milestone does not exist.
+type MilestoneExistsFunc = func(index iotago.MilestoneIndex) (*LatestMilestoneInfo, error)
+
+// oldStateFilePath returns the path the state file is renamed to while a milestone is issued.
+func (coo *Coordinator) oldStateFilePath() string {
+	return fmt.Sprintf("%s_old", coo.opts.stateFilePath)
+}
+
+// restoreStateFile restores the state file that was renamed before a milestone was sent.
+// It is used if the coordinator crashed before the new state file was written.
+func (coo *Coordinator) restoreStateFile() error {
+	if _, err := os.Stat(coo.oldStateFilePath()); err != nil {
+		return fmt.Errorf("state file not found: %v", coo.opts.stateFilePath)
+	}
+
+	coo.LogWarnf("state file not found, restoring it from %s", coo.oldStateFilePath())
+
+	if err := os.Rename(coo.oldStateFilePath(), coo.opts.stateFilePath); err != nil {
+		return fmt.Errorf("unable to restore coordinator state file: %w", err)
+	}
+
+	return nil
+}
+
+// resolveUnconfirmedMilestone checks whether a milestone that may have been sent, although sending it failed, was issued.
+// An issued milestone is adopted into the state, otherwise the state file is restored and the index can be used again.
+// A milestone unknown to the node may still be propagating or not be solid yet, so the index is only used again
+// if the node is synced up to the latest milestone of the coordinator, otherwise a soft error is returned and the check is repeated.
+// Without a MilestoneExistsFunc the check is not possible and a critical error is returned.
+// Returns whether the milestone was adopted. The caller must hold the milestoneLock.
+func (coo *Coordinator) resolveUnconfirmedMilestone() (bool, error) {
+	if coo.unconfirmedMilestoneIndex == 0 {
+		return false, nil
+	}
+
+	if coo.opts.milestoneExistsFunc == nil {
+		return false, common.CriticalError(fmt.Errorf("%w: milestone %d, enable the crash recovery to check whether it was issued", ErrMilestoneDeliveryUnknown, coo.unconfirmedMilestoneIndex))
+	}
+
+	state := *coo.state
+	if err := coo.recoverState(&state); err != nil {
+		// the node may be unavailable, the check is repeated before the next milestone
+		return false, common.SoftError(err)
+	}
+
+	adopted := state.LatestMilestoneIndex != coo.state.LatestMilestoneIndex
+	if !adopted {
+		if synced, nodeMilestoneIndex := coo.nodeSyncedUpTo(coo.state.LatestMilestoneIndex); !synced {
+			coo.logWarnw("node not synced, the check whether the milestone was issued is repeated", logFieldMilestoneIndex, coo.unconfirmedMilestoneIndex, logFieldNodeMilestoneIndex, nodeMilestoneIndex)
+
+			return false, common.SoftError(fmt.Errorf("%w: milestone %d, node not synced up to milestone %d", ErrMilestoneDeliveryUnknown, coo.unconfirmedMilestoneIndex, coo.state.LatestMilestoneIndex))
+		}
+
+		// recoverState only writes the state file if the milestone was adopted
+		if err := ioutils.WriteJSONToFile(coo.opts.stateFilePath, &state, 0660); err != nil {
+			return false, common.CriticalError(fmt.Errorf("failed to restore coordinator state file: %w", err))
+		}
+		coo.logWarnw("milestone was not issued, its index is used again", logFieldMilestoneIndex, coo.unconfirmedMilestoneIndex)
+	}
+
+	*coo.state = state
+	coo.publishState()
+	coo.unconfirmedMilestoneIndex = 0
+
+	return adopted, nil
+}
+
+// nodeSyncedUpTo returns whether the node is synced and knows the given milestone index, and the latest milestone index of the node.
+// Without a LatestMilestoneIndexFunc only the sync status of the node is checked.
+func (coo *Coordinator) nodeSyncedUpTo(index iotago.MilestoneIndex) (bool, iotago.MilestoneIndex) {
+	var nodeMilestoneIndex iotago.MilestoneIndex
+	if coo.opts.latestMilestoneIndexFunc != nil {
+		nodeMilestoneIndex = coo.opts.latestMilestoneIndexFunc()
+		if nodeMilestoneIndex < index {
+			return false, nodeMilestoneIndex
+		}
+	}
+
+	return coo.NodeSynced(), nodeMilestoneIndex
+}
+
+// recoverState adopts the next milestone into the given state if it was already sent to the network,
+// but the coordinator crashed before the state file was written.
+// This prevents issuing a second milestone with the same index.
+func (coo *Coordinator) recoverState(state *State) error {
+	nextMilestoneIndex := state.LatestMilestoneIndex + 1
+
+	milestone, err := coo.opts.milestoneExistsFunc(nextMilestoneIndex)
+	if err != nil {
+		return fmt.Errorf("failed to check whether milestone %d exists: %w", nextMilestoneIndex, err)
+	}
+
+	if milestone == nil {
+		return nil
+	}
+
+	coo.LogWarnf("milestone %d was already issued, adopting it into the coordinator state", nextMilestoneIndex)
+
+	// the block containing the milestone is unknown, so the next milestone can't reference it directly.
+	// this is the same as after bootstrapping a network at a start index.
+	state.LatestMilestoneBlockID = iotago.EmptyBlockID()
+	state.LatestMilestoneID = milestone.MilestoneID
+	state.LatestMilestoneIndex = milestone.Index
+	state.LatestMilestoneTime = time.Unix(int64(milestone.Timestamp), 0)
+	state.PendingBootstrap = false
+
+	if err := ioutils.WriteJSONToFile(coo.opts.stateFilePath, state, 0660); err != nil {
+		return fmt.Errorf("failed to update coordinator state file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package coordinator
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// TipsFunc returns the tips that are referenced by the next milestone.
+type TipsFunc func() (iotago.BlockIDs, error)
+
+// Run bootstraps the network if needed and issues a milestone every interval until the context is done,
+// so the coordinator can be used without a separate scheduler.
+// The interval can be changed at runtime, it is read again for every milestone.
+// Milestones are not issued while the coordinator is paused or the node signals back pressure.
+// Soft errors are triggered on the SoftError event and the milestone is retried at the next interval.
+// Returns the first critical error, or nil if the context is done or the coordinator is shut down.
+func (coo *Coordinator) Run(ctx context.Context, tipsFunc TipsFunc) error {
+	if _, err := coo.Bootstrap(); err != nil {
+		return err
+	}
+
+	for {
+		timer := time.NewTimer(coo.Interval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		case <-timer.C:
+		}
+
+		tips, err := tipsFunc()
+		if err != nil {
+			// no tips available, the milestone is retried at the next interval
+			coo.LogWarnf("failed to get tips for the next milestone: %s", err)
+
+			continue
+		}
+
+		if _, err := coo.IssueMilestone(tips); err != nil {
+			if errors.Is(err, ErrCoordinatorShutdown) {
+				return nil
+			}
+
+			if softErr := common.IsSoftError(err); softErr != nil {
+				// soft errors are already logged by the coordinator
+				if !SoftErrorEventTriggered(err) {
+					coo.Events.SoftError.Trigger(softErr)
+				}
+
+				continue
+			}
+
+			// critical errors and errors of unknown type stop the coordinator
+			return err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package coordinator
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// parallelSigningFunc wraps the given MilestoneSigningFunc to sign with every public key in a separate call,
+// using up to parallelism concurrent calls. The signatures are returned in the order of the public keys,
+// so the resulting milestone doesn't depend on the order the calls finish.
+func parallelSigningFunc(signingFunc iotago.MilestoneSigningFunc, parallelism int) iotago.MilestoneSigningFunc {
+	if parallelism < 2 {
+		return signingFunc
+	}
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		if len(pubKeys) < 2 {
+			return signingFunc(pubKeys, msEssence)
+		}
+
+		signatures := make([]iotago.MilestoneSignature, len(pubKeys))
+
+		var group errgroup.Group
+		group.SetLimit(parallelism)
+		for i := range pubKeys {
+			i := i
+			group.Go(func() error {
+				sigs, err := signingFunc(pubKeys[i:i+1], msEssence)
+				if err != nil {
+					return err
+				}
+
+				if len(sigs) != 1 {
+					return fmt.Errorf("%w: wanted 1 signature for public key %s but got %d", iotago.ErrMilestoneProducedSignaturesCountMismatch, iotago.EncodeHex(pubKeys[i][:]), len(sigs))
+				}
+				signatures[i] = sigs[0]
+
+				return nil
+			})
+		}
+
+		if err := group.Wait(); err != nil {
+			return nil, err
+		}
+
+		return signatures, nil
+	}
+}
@@ -0,0 +1,74 @@
+package coordinator
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestParallelSigningFunc(t *testing.T) {
+	keyPairs := make(iotago.MilestonePublicKeyMapping)
+	pubKeys := make([]iotago.MilestonePublicKey, 0, 6)
+	for i := 0; i < 6; i++ {
+		pubKey, privKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		var milestonePubKey iotago.MilestonePublicKey
+		copy(milestonePubKey[:], pubKey)
+		keyPairs[milestonePubKey] = privKey
+		pubKeys = append(pubKeys, milestonePubKey)
+	}
+	inMemorySigningFunc := iotago.InMemoryEd25519MilestoneSigner(keyPairs)
+
+	var inFlight, maxInFlight atomic.Int32
+	signingFunc := parallelSigningFunc(func(keys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		require.Len(t, keys, 1)
+
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			maximum := maxInFlight.Load()
+			if current <= maximum || maxInFlight.CompareAndSwap(maximum, current) {
+				break
+			}
+		}
+
+		// the later keys finish first
+		time.Sleep(time.Duration(6-int(keys[0][0])%6) * time.Millisecond)
+
+		return inMemorySigningFunc(keys, msEssence)
+	}, 2)
+
+	msEssence := []byte("milestone essence")
+	signatures, err := signingFunc(pubKeys, msEssence)
+	require.NoError(t, err)
+	require.LessOrEqual(t, maxInFlight.Load(), int32(2))
+
+	// the signatures are in the order of the public keys
+	expectedSignatures, err := inMemorySigningFunc(pubKeys, msEssence)
+	require.NoError(t, err)
+	require.Equal(t, expectedSignatures, signatures)
+
+	// errors of a single key fail the signing
+	signingErr := errors.New("signing failed")
+	_, err = parallelSigningFunc(func(keys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		if keys[0] == pubKeys[3] {
+			return nil, signingErr
+		}
+
+		return inMemorySigningFunc(keys, msEssence)
+	}, 2)(pubKeys, msEssence)
+	require.ErrorIs(t, err, signingErr)
+
+	// a wrong amount of signatures fails the signing
+	_, err = parallelSigningFunc(func(_ []iotago.MilestonePublicKey, _ []byte) ([]iotago.MilestoneSignature, error) {
+		return nil, nil
+	}, 2)(pubKeys, msEssence)
+	require.ErrorIs(t, err, iotago.ErrMilestoneProducedSignaturesCountMismatch)
+}
@@ -42,6 +42,19 @@ func NewInMemoryEd25519MilestoneSignerProvider(privateKeys []ed25519.PrivateKey,
 	}
 }
 
+// NewInMemorySignerProvider creates a new InMemoryEd25519MilestoneSignerProvider that signs every milestone with all given keys.
+// It is intended for tests, which can issue milestones end-to-end without a key manager or an external signer.
+// Never use it in production, the private keys are kept in memory.
+func NewInMemorySignerProvider(privateKeys ...ed25519.PrivateKey) *InMemoryEd25519MilestoneSignerProvider {
+	keyManager := keymanager.New()
+	for _, privateKey := range privateKeys {
+		//nolint:forcetypeassert // the public key of an ed25519.PrivateKey is always an ed25519.PublicKey
+		keyManager.AddKeyRange(privateKey.Public().(ed25519.PublicKey), 0, 0)
+	}
+
+	return NewInMemoryEd25519MilestoneSignerProvider(privateKeys, keyManager, len(privateKeys))
+}
+
 // MilestoneIndexSigner returns a new signer for the milestone index.
 func (p *InMemoryEd25519MilestoneSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
 
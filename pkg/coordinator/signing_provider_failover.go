@@ -0,0 +1,117 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// FailoverSignerProvider is a MilestoneSignerProvider that wraps a prioritized list of providers.
+// If a provider fails to sign a milestone, the next provider is used.
+// The public keys of the first provider are used for the milestone,
+// a backup provider is only used if it can sign with the same public keys,
+// so the milestone structure stays the same regardless of which provider signed it.
+type FailoverSignerProvider struct {
+	*logger.WrappedLogger
+	providers []MilestoneSignerProvider
+	// the amount of milestones that were not signed by the first provider.
+	failoverCount atomic.Uint64
+}
+
+// NewFailoverSignerProvider creates a new FailoverSignerProvider.
+// The providers are used in the given order, all of them must use the same amount of public keys.
+func NewFailoverSignerProvider(log *logger.Logger, providers ...MilestoneSignerProvider) (*FailoverSignerProvider, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("no milestone signer providers given")
+	}
+
+	for i, provider := range providers[1:] {
+		if provider.PublicKeysCount() != providers[0].PublicKeysCount() {
+			return nil, fmt.Errorf("milestone signer provider %d uses %d public keys, but the primary uses %d", i+1, provider.PublicKeysCount(), providers[0].PublicKeysCount())
+		}
+	}
+
+	return &FailoverSignerProvider{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		providers:     providers,
+	}, nil
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (p *FailoverSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
+	signers := make([]MilestoneIndexSigner, len(p.providers))
+	for i, provider := range p.providers {
+		signers[i] = provider.MilestoneIndexSigner(index)
+	}
+
+	return &InsecureRemoteEd25519MilestoneIndexSigner{
+		pubKeys:   signers[0].PublicKeys(),
+		pubKeySet: signers[0].PublicKeysSet(),
+		signingFunc: func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+			return p.signMilestone(index, signers, pubKeys, msEssence)
+		},
+	}
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *FailoverSignerProvider) PublicKeysCount() int {
+	return p.providers[0].PublicKeysCount()
+}
+
+// FailoverCount returns the amount of milestones that were not signed by the first provider.
+func (p *FailoverSignerProvider) FailoverCount() uint64 {
+	return p.failoverCount.Load()
+}
+
+// signMilestone signs the milestone essence with the first provider that succeeds.
+func (p *FailoverSignerProvider) signMilestone(index iotago.MilestoneIndex, signers []MilestoneIndexSigner, pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+	var lastErr error
+	for i, signer := range signers {
+		if !signerHasPublicKeys(signer, pubKeys) {
+			p.LogWarnf("milestone signer provider %d can't sign milestone %d with the public keys of the primary", i, index)
+
+			continue
+		}
+
+		signatures, err := signer.SigningFunc()(pubKeys, msEssence)
+		if err != nil {
+			p.LogWarnf("milestone signer provider %d failed to sign milestone %d: %s", i, index, err)
+			lastErr = err
+
+			continue
+		}
+
+		if i > 0 {
+			p.failoverCount.Add(1)
+			p.LogWarnf("milestone %d signed by backup milestone signer provider %d, failovers: %d", index, i, p.failoverCount.Load())
+		}
+
+		return signatures, nil
+	}
+
+	if lastErr == nil {
+		return nil, errors.New("all milestone signer providers failed, no provider can sign with the public keys of the primary")
+	}
+
+	return nil, fmt.Errorf("all milestone signer providers failed, last error: %w", lastErr)
+}
+
+// signerHasPublicKeys returns whether the signer uses all the given public keys.
+func signerHasPublicKeys(signer MilestoneIndexSigner, pubKeys []iotago.MilestonePublicKey) bool {
+	signerPubKeys := make(map[iotago.MilestonePublicKey]struct{}, len(signer.PublicKeys()))
+	for _, pubKey := range signer.PublicKeys() {
+		signerPubKeys[pubKey] = struct{}{}
+	}
+
+	for _, pubKey := range pubKeys {
+		if _, exists := signerPubKeys[pubKey]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,82 @@
+package coordinator_test
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+// failingSignerProvider is a MilestoneSignerProvider whose signing always fails.
+type failingSignerProvider struct {
+	coordinator.MilestoneSignerProvider
+}
+
+func (p *failingSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) coordinator.MilestoneIndexSigner {
+	return &failingIndexSigner{MilestoneIndexSigner: p.MilestoneSignerProvider.MilestoneIndexSigner(index)}
+}
+
+type failingIndexSigner struct {
+	coordinator.MilestoneIndexSigner
+}
+
+func (s *failingIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return func(_ []iotago.MilestonePublicKey, _ []byte) ([]iotago.MilestoneSignature, error) {
+		return nil, errors.New("signer unavailable")
+	}
+}
+
+func TestFailoverSignerProvider(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	keyManager := keymanager.New()
+	keyManager.AddKeyRange(pubKey, 0, 0)
+
+	inMemoryProvider := coordinator.NewInMemoryEd25519MilestoneSignerProvider([]ed25519.PrivateKey{privKey}, keyManager, 1)
+
+	failoverProvider, err := coordinator.NewFailoverSignerProvider(nil, &failingSignerProvider{inMemoryProvider}, inMemoryProvider)
+	require.NoError(t, err)
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithSignerProvider(failoverProvider), coordinator.WithSigningRetryAmount(1))
+	require.EqualValues(t, 1, failoverProvider.FailoverCount())
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, failoverProvider.FailoverCount())
+
+	for _, block := range sender.sentBlocks() {
+		milestonePayload, ok := block.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		require.NoError(t, milestonePayload.VerifySignatures(1, keyManager.PublicKeysSetForMilestoneIndex(milestonePayload.Index)))
+	}
+}
+
+func TestFailoverSignerProviderDifferentPublicKeys(t *testing.T) {
+	otherPubKey, otherPrivKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	primaryProvider := testSignerProvider(t)
+
+	// the backup can't sign with the public keys of the primary
+	otherKeyManager := keymanager.New()
+	otherKeyManager.AddKeyRange(otherPubKey, 0, 0)
+	backupProvider := coordinator.NewInMemoryEd25519MilestoneSignerProvider([]ed25519.PrivateKey{otherPrivKey}, otherKeyManager, 1)
+
+	failoverProvider, err := coordinator.NewFailoverSignerProvider(nil, &failingSignerProvider{primaryProvider}, backupProvider)
+	require.NoError(t, err)
+
+	indexSigner := failoverProvider.MilestoneIndexSigner(1)
+	_, err = indexSigner.SigningFunc()(indexSigner.PublicKeys(), []byte("essence"))
+	require.Error(t, err)
+	require.Zero(t, failoverProvider.FailoverCount())
+
+	// the amount of public keys must match
+	_, err = coordinator.NewFailoverSignerProvider(nil, primaryProvider, coordinator.NewInMemoryEd25519MilestoneSignerProvider(nil, otherKeyManager, 2))
+	require.Error(t, err)
+}
@@ -0,0 +1,26 @@
+package coordinator
+
+import (
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrHSMUnavailable is returned if the PKCS#11 module of the HSM can't be used.
+	ErrHSMUnavailable = errors.New("HSM unavailable")
+	// ErrHSMKeyNotFound is returned if a configured key can't be found in the HSM.
+	ErrHSMKeyNotFound = errors.New("HSM key not found")
+)
+
+// HSMSignerConfig defines the HSM used by the HSMSigner.
+type HSMSignerConfig struct {
+	// the path to the PKCS#11 module of the HSM.
+	ModulePath string
+	// the slot of the token holding the keys.
+	SlotID uint
+	// the PIN of the user of the token.
+	PIN string
+	// the labels of the private keys in the HSM, mapped by their public keys.
+	KeyLabels map[iotago.MilestonePublicKey]string
+}
@@ -0,0 +1,210 @@
+//go:build cgo
+
+package coordinator
+
+import (
+	"fmt"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+const (
+	// the PKCS#11 v3.0 mechanism for EdDSA signatures, not defined by the pkcs11 package.
+	ckmEdDSA = 0x00001057
+)
+
+// pkcs11Context contains the functions of the PKCS#11 module used by the HSMSigner.
+type pkcs11Context interface {
+	Initialize() error
+	Finalize() error
+	Destroy()
+	OpenSession(slotID uint, flags uint) (pkcs11.SessionHandle, error)
+	CloseSession(sh pkcs11.SessionHandle) error
+	Login(sh pkcs11.SessionHandle, userType uint, pin string) error
+	Logout(sh pkcs11.SessionHandle) error
+	FindObjectsInit(sh pkcs11.SessionHandle, temp []*pkcs11.Attribute) error
+	FindObjects(sh pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error)
+	FindObjectsFinal(sh pkcs11.SessionHandle) error
+	SignInit(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error
+	Sign(sh pkcs11.SessionHandle, message []byte) ([]byte, error)
+}
+
+// HSMSigner is a MilestoneSignerProvider that signs milestones inside a hardware security module via PKCS#11.
+// The private keys never leave the HSM.
+type HSMSigner struct {
+	ctx             pkcs11Context
+	session         pkcs11.SessionHandle
+	keyHandles      map[iotago.MilestonePublicKey]pkcs11.ObjectHandle
+	keyManger       *keymanager.KeyManager
+	publicKeysCount int
+
+	// PKCS#11 sessions must not be used concurrently.
+	sessionLock syncutils.Mutex
+}
+
+// NewHSMSigner creates a new HSMSigner.
+// It loads the PKCS#11 module, logs into the token and looks up the configured keys.
+func NewHSMSigner(config *HSMSignerConfig, keyManager *keymanager.KeyManager, publicKeysCount int) (*HSMSigner, error) {
+	ctx := pkcs11.New(config.ModulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("%w: unable to load PKCS#11 module %s", ErrHSMUnavailable, config.ModulePath)
+	}
+
+	return newHSMSigner(ctx, config, keyManager, publicKeysCount)
+}
+
+func newHSMSigner(ctx pkcs11Context, config *HSMSignerConfig, keyManager *keymanager.KeyManager, publicKeysCount int) (*HSMSigner, error) {
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+
+		return nil, fmt.Errorf("%w: unable to initialize PKCS#11 module %s: %s", ErrHSMUnavailable, config.ModulePath, err)
+	}
+
+	session, err := ctx.OpenSession(config.SlotID, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+
+		return nil, fmt.Errorf("%w: unable to open session on slot %d: %s", ErrHSMUnavailable, config.SlotID, err)
+	}
+
+	signer := &HSMSigner{
+		ctx:             ctx,
+		session:         session,
+		keyHandles:      make(map[iotago.MilestonePublicKey]pkcs11.ObjectHandle, len(config.KeyLabels)),
+		keyManger:       keyManager,
+		publicKeysCount: publicKeysCount,
+	}
+
+	if err := ctx.Login(session, pkcs11.CKU_USER, config.PIN); err != nil {
+		_ = signer.close(false)
+
+		return nil, fmt.Errorf("%w: unable to login to slot %d: %s", ErrHSMUnavailable, config.SlotID, err)
+	}
+
+	for pubKey, label := range config.KeyLabels {
+		handle, err := signer.findPrivateKey(label)
+		if err != nil {
+			_ = signer.close(true)
+
+			return nil, err
+		}
+		signer.keyHandles[pubKey] = handle
+	}
+
+	return signer, nil
+}
+
+// findPrivateKey returns the handle of the private key with the given label.
+func (s *HSMSigner) findPrivateKey(label string) (pkcs11.ObjectHandle, error) {
+	if err := s.ctx.FindObjectsInit(s.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}); err != nil {
+		return 0, fmt.Errorf("unable to search HSM key %s: %w", label, err)
+	}
+
+	handles, _, err := s.ctx.FindObjects(s.session, 2)
+	if errFinal := s.ctx.FindObjectsFinal(s.session); err == nil {
+		err = errFinal
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to search HSM key %s: %w", label, err)
+	}
+
+	switch len(handles) {
+	case 0:
+		return 0, fmt.Errorf("%w: %s", ErrHSMKeyNotFound, label)
+	case 1:
+		return handles[0], nil
+	default:
+		return 0, fmt.Errorf("HSM key label %s is ambiguous", label)
+	}
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+// Only the public keys valid for the milestone index with a key in the HSM are used.
+func (s *HSMSigner) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
+
+	pubKeys := make([]iotago.MilestonePublicKey, 0, s.publicKeysCount)
+	for _, pubKey := range s.keyManger.PublicKeysForMilestoneIndex(index) {
+		if len(pubKeys) == s.publicKeysCount {
+			break
+		}
+
+		if _, exists := s.keyHandles[pubKey]; exists {
+			pubKeys = append(pubKeys, pubKey)
+		}
+	}
+
+	return &InsecureRemoteEd25519MilestoneIndexSigner{
+		pubKeys:     pubKeys,
+		pubKeySet:   s.keyManger.PublicKeysSetForMilestoneIndex(index),
+		signingFunc: s.signMilestone,
+	}
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (s *HSMSigner) PublicKeysCount() int {
+	return s.publicKeysCount
+}
+
+// Close logs out of the token and unloads the PKCS#11 module.
+// The module is unloaded in any case, the first error is returned.
+func (s *HSMSigner) Close() error {
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	return s.close(true)
+}
+
+func (s *HSMSigner) close(logout bool) error {
+	var err error
+	if logout {
+		err = s.ctx.Logout(s.session)
+	}
+	if errClose := s.ctx.CloseSession(s.session); err == nil {
+		err = errClose
+	}
+	if errFinalize := s.ctx.Finalize(); err == nil {
+		err = errFinalize
+	}
+	s.ctx.Destroy()
+
+	return err
+}
+
+// signMilestone signs the milestone essence with the HSM keys of the given public keys.
+// Transient errors are retried by the coordinator according to the signing retry options.
+func (s *HSMSigner) signMilestone(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	signatures := make([]iotago.MilestoneSignature, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		handle, exists := s.keyHandles[pubKey]
+		if !exists {
+			return nil, fmt.Errorf("%w: public key %s", ErrHSMKeyNotFound, iotago.EncodeHex(pubKey[:]))
+		}
+
+		if err := s.ctx.SignInit(s.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(ckmEdDSA, nil)}, handle); err != nil {
+			return nil, fmt.Errorf("unable to initialize HSM signing: %w", err)
+		}
+
+		signature, err := s.ctx.Sign(s.session, msEssence)
+		if err != nil {
+			return nil, fmt.Errorf("HSM signing failed: %w", err)
+		}
+
+		if len(signature) != len(signatures[i]) {
+			return nil, fmt.Errorf("HSM returned a signature with invalid length: %d", len(signature))
+		}
+		copy(signatures[i][:], signature)
+	}
+
+	return signatures, nil
+}
@@ -0,0 +1,135 @@
+//go:build cgo
+
+package coordinator
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+// testPKCS11Context emulates a PKCS#11 module holding ed25519 private keys.
+type testPKCS11Context struct {
+	initializeErr error
+	keysByLabel   map[string]ed25519.PrivateKey
+	handles       []string
+	searchLabel   string
+	signingKey    ed25519.PrivateKey
+	loggedIn      bool
+	destroyed     bool
+}
+
+func (c *testPKCS11Context) Initialize() error { return c.initializeErr }
+func (c *testPKCS11Context) Finalize() error   { return nil }
+func (c *testPKCS11Context) Destroy()          { c.destroyed = true }
+
+func (c *testPKCS11Context) OpenSession(_ uint, _ uint) (pkcs11.SessionHandle, error) {
+	return 1, nil
+}
+
+func (c *testPKCS11Context) CloseSession(_ pkcs11.SessionHandle) error { return nil }
+
+func (c *testPKCS11Context) Login(_ pkcs11.SessionHandle, _ uint, _ string) error {
+	c.loggedIn = true
+
+	return nil
+}
+
+func (c *testPKCS11Context) Logout(_ pkcs11.SessionHandle) error {
+	c.loggedIn = false
+
+	return nil
+}
+
+func (c *testPKCS11Context) FindObjectsInit(_ pkcs11.SessionHandle, temp []*pkcs11.Attribute) error {
+	for _, attr := range temp {
+		if attr.Type == pkcs11.CKA_LABEL {
+			c.searchLabel = string(attr.Value)
+		}
+	}
+
+	return nil
+}
+
+func (c *testPKCS11Context) FindObjects(_ pkcs11.SessionHandle, _ int) ([]pkcs11.ObjectHandle, bool, error) {
+	if _, exists := c.keysByLabel[c.searchLabel]; !exists {
+		return nil, false, nil
+	}
+	c.handles = append(c.handles, c.searchLabel)
+
+	return []pkcs11.ObjectHandle{pkcs11.ObjectHandle(len(c.handles) - 1)}, false, nil
+}
+
+func (c *testPKCS11Context) FindObjectsFinal(_ pkcs11.SessionHandle) error { return nil }
+
+func (c *testPKCS11Context) SignInit(_ pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error {
+	if !c.loggedIn {
+		return pkcs11.Error(pkcs11.CKR_USER_NOT_LOGGED_IN)
+	}
+	if len(m) != 1 || m[0].Mechanism != ckmEdDSA {
+		return pkcs11.Error(pkcs11.CKR_MECHANISM_INVALID)
+	}
+	c.signingKey = c.keysByLabel[c.handles[o]]
+
+	return nil
+}
+
+func (c *testPKCS11Context) Sign(_ pkcs11.SessionHandle, message []byte) ([]byte, error) {
+	return ed25519.Sign(c.signingKey, message), nil
+}
+
+func TestHSMSigner(t *testing.T) {
+	pubKey1, privKey1, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	pubKey2, privKey2, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	keyManager := keymanager.New()
+	keyManager.AddKeyRange(pubKey1, 0, 0)
+	keyManager.AddKeyRange(pubKey2, 0, 0)
+
+	var milestonePubKey1, milestonePubKey2 iotago.MilestonePublicKey
+	copy(milestonePubKey1[:], pubKey1)
+	copy(milestonePubKey2[:], pubKey2)
+
+	ctx := &testPKCS11Context{keysByLabel: map[string]ed25519.PrivateKey{"coo-1": privKey1, "coo-2": privKey2}}
+	signer, err := newHSMSigner(ctx, &HSMSignerConfig{
+		KeyLabels: map[iotago.MilestonePublicKey]string{milestonePubKey1: "coo-1", milestonePubKey2: "coo-2"},
+	}, keyManager, 2)
+	require.NoError(t, err)
+
+	indexSigner := signer.MilestoneIndexSigner(1)
+	require.Len(t, indexSigner.PublicKeys(), 2)
+
+	milestone := iotago.NewMilestone(1, 1, 2, iotago.MilestoneID{}, iotago.BlockIDs{iotago.EmptyBlockID()}, iotago.MilestoneMerkleProof{}, iotago.MilestoneMerkleProof{})
+	require.NoError(t, milestone.Sign(indexSigner.PublicKeys(), indexSigner.SigningFunc()))
+	require.NoError(t, milestone.VerifySignatures(2, indexSigner.PublicKeysSet()))
+
+	require.NoError(t, signer.Close())
+	require.False(t, ctx.loggedIn)
+	require.True(t, ctx.destroyed)
+}
+
+func TestHSMSignerErrors(t *testing.T) {
+	var milestonePubKey iotago.MilestonePublicKey
+
+	// the module is unavailable
+	ctx := &testPKCS11Context{initializeErr: errors.New("no token present")}
+	_, err := newHSMSigner(ctx, &HSMSignerConfig{}, keymanager.New(), 1)
+	require.ErrorIs(t, err, ErrHSMUnavailable)
+	require.True(t, ctx.destroyed)
+
+	// a configured key is missing
+	ctx = &testPKCS11Context{keysByLabel: map[string]ed25519.PrivateKey{}}
+	_, err = newHSMSigner(ctx, &HSMSignerConfig{
+		KeyLabels: map[iotago.MilestonePublicKey]string{milestonePubKey: "coo-1"},
+	}, keymanager.New(), 1)
+	require.ErrorIs(t, err, ErrHSMKeyNotFound)
+	require.True(t, ctx.destroyed)
+}
@@ -0,0 +1,38 @@
+//go:build !cgo
+
+package coordinator
+
+import (
+	"fmt"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+// HSMSigner is a MilestoneSignerProvider that signs milestones inside a hardware security module via PKCS#11.
+// The PKCS#11 module is loaded via cgo, so this build doesn't support it and NewHSMSigner always fails.
+type HSMSigner struct{}
+
+// NewHSMSigner returns an error, because the HSM signing provider needs a build with cgo enabled.
+func NewHSMSigner(config *HSMSignerConfig, _ *keymanager.KeyManager, _ int) (*HSMSigner, error) {
+	return nil, fmt.Errorf("%w: unable to load PKCS#11 module %s, the HSM signing provider requires a build with cgo enabled", ErrHSMUnavailable, config.ModulePath)
+}
+
+// MilestoneIndexSigner returns a signer without any public keys.
+func (s *HSMSigner) MilestoneIndexSigner(_ iotago.MilestoneIndex) MilestoneIndexSigner {
+	return &InsecureRemoteEd25519MilestoneIndexSigner{
+		signingFunc: func(_ []iotago.MilestonePublicKey, _ []byte) ([]iotago.MilestoneSignature, error) {
+			return nil, ErrHSMUnavailable
+		},
+	}
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (s *HSMSigner) PublicKeysCount() int {
+	return 0
+}
+
+// Close does nothing, there is no PKCS#11 module to unload.
+func (s *HSMSigner) Close() error {
+	return nil
+}
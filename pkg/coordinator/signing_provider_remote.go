@@ -0,0 +1,157 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+	"github.com/iotaledger/iota.go/v3/remotesigner"
+)
+
+const (
+	// the timeout of a single request to the remote signer.
+	remoteSignerRequestTimeout = 5 * time.Second
+)
+
+// RemoteSigner is a MilestoneSignerProvider that keeps the signing keys out of the coordinator process.
+// The milestone essence is sent to a remote signer via gRPC, which returns the signatures.
+// The connection is established on the first signing request and re-established after it failed.
+type RemoteSigner struct {
+	remoteEndpoint  string
+	dialOpts        []grpc.DialOption
+	keyManger       *keymanager.KeyManager
+	publicKeysCount int
+
+	// the current connection to the remote signer.
+	conn *grpc.ClientConn
+	// used to protect the connection.
+	connLock syncutils.Mutex
+}
+
+// NewRemoteSigner creates a new RemoteSigner.
+// The dial options are used to establish the connection to the remote signer, e.g. the transport credentials.
+func NewRemoteSigner(remoteEndpoint string, keyManager *keymanager.KeyManager, publicKeysCount int, dialOpts ...grpc.DialOption) *RemoteSigner {
+
+	return &RemoteSigner{
+		remoteEndpoint:  remoteEndpoint,
+		dialOpts:        dialOpts,
+		keyManger:       keyManager,
+		publicKeysCount: publicKeysCount,
+	}
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (s *RemoteSigner) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
+
+	return &InsecureRemoteEd25519MilestoneIndexSigner{
+		pubKeys:     s.keyManger.PublicKeysForMilestoneIndex(index),
+		pubKeySet:   s.keyManger.PublicKeysSetForMilestoneIndex(index),
+		signingFunc: s.signMilestone,
+	}
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (s *RemoteSigner) PublicKeysCount() int {
+	return s.publicKeysCount
+}
+
+// Close closes the connection to the remote signer.
+func (s *RemoteSigner) Close() error {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// connection returns the connection to the remote signer.
+// A new connection is established if there is none or the current one was shut down.
+func (s *RemoteSigner) connection() (*grpc.ClientConn, error) {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+
+	if s.conn != nil && s.conn.GetState() != connectivity.Shutdown {
+		return s.conn, nil
+	}
+
+	conn, err := grpc.Dial(s.remoteEndpoint, s.dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to remote signer %s: %w", s.remoteEndpoint, err)
+	}
+	s.conn = conn
+
+	return conn, nil
+}
+
+// resetConnection closes the given connection, so the next signing request reconnects.
+func (s *RemoteSigner) resetConnection(conn *grpc.ClientConn) {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+
+	// the connection could already be replaced by a concurrent request
+	if s.conn != conn {
+		return
+	}
+
+	_ = s.conn.Close()
+	s.conn = nil
+}
+
+// signMilestone requests the signatures of the milestone essence from the remote signer.
+// Transient errors are retried by the coordinator according to the signing retry options.
+func (s *RemoteSigner) signMilestone(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+	conn, err := s.connection()
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeysUnbound := make([][]byte, len(pubKeys))
+	for i := range pubKeys {
+		pubKeysUnbound[i] = make([]byte, len(pubKeys[i]))
+		copy(pubKeysUnbound[i], pubKeys[i][:])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSignerRequestTimeout)
+	defer cancel()
+
+	response, err := remotesigner.NewSignatureDispatcherClient(conn).SignMilestone(ctx, &remotesigner.SignMilestoneRequest{
+		PubKeys:   pubKeysUnbound,
+		MsEssence: msEssence,
+	})
+	if err != nil {
+		if status.Code(err) == codes.Unavailable {
+			s.resetConnection(conn)
+		}
+
+		return nil, fmt.Errorf("remote signer %s failed to sign milestone: %w", s.remoteEndpoint, err)
+	}
+
+	sigs := response.GetSignatures()
+	if len(sigs) != len(pubKeys) {
+		return nil, fmt.Errorf("%w: remote signer %s did not provide the correct count of signatures", iotago.ErrMilestoneProducedSignaturesCountMismatch, s.remoteEndpoint)
+	}
+
+	signatures := make([]iotago.MilestoneSignature, len(sigs))
+	for i := range sigs {
+		if len(sigs[i]) != len(signatures[i]) {
+			return nil, fmt.Errorf("remote signer %s returned a signature with invalid length: %d", s.remoteEndpoint, len(sigs[i]))
+		}
+		copy(signatures[i][:], sigs[i])
+	}
+
+	return signatures, nil
+}
@@ -0,0 +1,89 @@
+package coordinator_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+	"github.com/iotaledger/iota.go/v3/remotesigner"
+)
+
+// testSignatureDispatcher signs milestones with an in memory key.
+type testSignatureDispatcher struct {
+	remotesigner.UnimplementedSignatureDispatcherServer
+	privateKey ed25519.PrivateKey
+}
+
+func (d *testSignatureDispatcher) SignMilestone(_ context.Context, req *remotesigner.SignMilestoneRequest) (*remotesigner.SignMilestoneResponse, error) {
+	signatures := make([][]byte, len(req.GetPubKeys()))
+	for i := range signatures {
+		signatures[i] = ed25519.Sign(d.privateKey, req.GetMsEssence())
+	}
+
+	return &remotesigner.SignMilestoneResponse{Signatures: signatures}, nil
+}
+
+// startTestRemoteSigner starts a remote signer at the given address.
+func startTestRemoteSigner(t *testing.T, address string, privateKey ed25519.PrivateKey) (string, func()) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	remotesigner.RegisterSignatureDispatcherServer(server, &testSignatureDispatcher{privateKey: privateKey})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), server.Stop
+}
+
+func TestRemoteSigner(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	address, stopServer := startTestRemoteSigner(t, "127.0.0.1:0", privKey)
+
+	keyManager := keymanager.New()
+	keyManager.AddKeyRange(pubKey, 0, 0)
+
+	remoteSigner := coordinator.NewRemoteSigner(address, keyManager, 1, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	t.Cleanup(func() { _ = remoteSigner.Close() })
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithSignerProvider(remoteSigner),
+		coordinator.WithSigningRetryAmount(1),
+	)
+
+	verifyLastMilestone := func() {
+		sentBlocks := sender.sentBlocks()
+		milestonePayload, ok := sentBlocks[len(sentBlocks)-1].Payload.(*iotago.Milestone)
+		require.True(t, ok)
+		require.NoError(t, milestonePayload.VerifySignatures(1, keyManager.PublicKeysSetForMilestoneIndex(milestonePayload.Index)))
+	}
+	verifyLastMilestone()
+
+	// signing fails while the remote signer is unavailable
+	stopServer()
+	_, err = coo.IssueMilestone(nil)
+	require.Error(t, err)
+
+	// the connection is re-established after the remote signer is available again
+	startTestRemoteSigner(t, address, privKey)
+
+	coo, sender = newBootstrappedTestCoordinator(t, nil,
+		coordinator.WithSignerProvider(remoteSigner),
+		coordinator.WithSigningRetryAmount(1),
+	)
+	verifyLastMilestone()
+}
@@ -0,0 +1,129 @@
+package coordinator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrSigningKeyScheduleGap is returned if the signing key rotation schedule doesn't provide enough public keys for a milestone index.
+	ErrSigningKeyScheduleGap = errors.New("signing key rotation schedule has a gap")
+)
+
+// SigningKeyRotation defines the milestone signer provider that is used from a certain milestone index on.
+type SigningKeyRotation struct {
+	// the first milestone index signed by the provider.
+	// the provider is used until the start index of the next rotation.
+	StartIndex iotago.MilestoneIndex
+	// the provider used to sign the milestones of the rotation.
+	Provider MilestoneSignerProvider
+}
+
+// RotatingSignerProvider is a MilestoneSignerProvider that rotates the signing keys at configured milestone indices.
+// Every milestone is signed by the provider of the latest rotation that started at or before its index,
+// so the public keys and the signers used for a milestone change at the start index of every rotation.
+type RotatingSignerProvider struct {
+	// the rotations ordered by their start index.
+	rotations []SigningKeyRotation
+}
+
+// NewRotatingSignerProvider creates a new RotatingSignerProvider.
+// The start indices of the rotations must be unique and all providers must use the same amount of public keys.
+func NewRotatingSignerProvider(rotations ...SigningKeyRotation) (*RotatingSignerProvider, error) {
+	if len(rotations) == 0 {
+		return nil, errors.New("no signing key rotations given")
+	}
+
+	sortedRotations := append([]SigningKeyRotation{}, rotations...)
+	sort.SliceStable(sortedRotations, func(i, j int) bool {
+		return sortedRotations[i].StartIndex < sortedRotations[j].StartIndex
+	})
+
+	for i, rotation := range sortedRotations {
+		if rotation.Provider == nil {
+			return nil, fmt.Errorf("no milestone signer provider given for the signing key rotation at milestone %d", rotation.StartIndex)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if rotation.StartIndex == sortedRotations[i-1].StartIndex {
+			return nil, fmt.Errorf("multiple signing key rotations start at milestone %d", rotation.StartIndex)
+		}
+
+		if rotation.Provider.PublicKeysCount() != sortedRotations[0].Provider.PublicKeysCount() {
+			return nil, fmt.Errorf("signing key rotation at milestone %d uses %d public keys, but the rotation at milestone %d uses %d", rotation.StartIndex, rotation.Provider.PublicKeysCount(), sortedRotations[0].StartIndex, sortedRotations[0].Provider.PublicKeysCount())
+		}
+	}
+
+	return &RotatingSignerProvider{
+		rotations: sortedRotations,
+	}, nil
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+// If no rotation started at or before the index, the signer has no public keys and signing fails.
+func (p *RotatingSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
+	rotation, exists := p.ActiveRotation(index)
+	if !exists {
+		return &InsecureRemoteEd25519MilestoneIndexSigner{
+			pubKeys:   []iotago.MilestonePublicKey{},
+			pubKeySet: iotago.MilestonePublicKeySet{},
+			signingFunc: func(_ []iotago.MilestonePublicKey, _ []byte) ([]iotago.MilestoneSignature, error) {
+				return nil, fmt.Errorf("%w: no signing key rotation started at or before milestone %d", ErrSigningKeyScheduleGap, index)
+			},
+		}
+	}
+
+	return rotation.Provider.MilestoneIndexSigner(index)
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *RotatingSignerProvider) PublicKeysCount() int {
+	return p.rotations[0].Provider.PublicKeysCount()
+}
+
+// ActiveRotation returns the rotation that signs the milestone with the given index.
+// Returns false if no rotation started at or before the index.
+func (p *RotatingSignerProvider) ActiveRotation(index iotago.MilestoneIndex) (SigningKeyRotation, bool) {
+	// the index of the first rotation that starts after the given index
+	next := sort.Search(len(p.rotations), func(i int) bool {
+		return p.rotations[i].StartIndex > index
+	})
+	if next == 0 {
+		return SigningKeyRotation{}, false
+	}
+
+	return p.rotations[next-1], true
+}
+
+// Rotations returns a copy of the rotations ordered by their start index.
+func (p *RotatingSignerProvider) Rotations() []SigningKeyRotation {
+	return append([]SigningKeyRotation{}, p.rotations...)
+}
+
+// validateAround checks that the schedule provides enough public keys for the milestone with the given index,
+// and for the first milestone of the next rotation, so the coordinator doesn't run into a gap at the next switch.
+func (p *RotatingSignerProvider) validateAround(index iotago.MilestoneIndex) error {
+	indices := []iotago.MilestoneIndex{index}
+
+	next := sort.Search(len(p.rotations), func(i int) bool {
+		return p.rotations[i].StartIndex > index
+	})
+	if next < len(p.rotations) {
+		indices = append(indices, p.rotations[next].StartIndex)
+	}
+
+	for _, milestoneIndex := range indices {
+		if keysCount := len(p.MilestoneIndexSigner(milestoneIndex).PublicKeys()); keysCount < p.PublicKeysCount() {
+			return fmt.Errorf("%w: not enough valid public keys for milestone %d, got: %d, needed: %d", ErrSigningKeyScheduleGap, milestoneIndex, keysCount, p.PublicKeysCount())
+		}
+	}
+
+	return nil
+}
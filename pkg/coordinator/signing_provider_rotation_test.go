@@ -0,0 +1,141 @@
+package coordinator_test
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+// testRotationSignerProvider creates a signer provider with a new key that is valid from startIndex to endIndex.
+func testRotationSignerProvider(t *testing.T, startIndex iotago.MilestoneIndex, endIndex iotago.MilestoneIndex) (coordinator.MilestoneSignerProvider, iotago.MilestonePublicKey) {
+	t.Helper()
+
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	keyManager := keymanager.New()
+	keyManager.AddKeyRange(pubKey, startIndex, endIndex)
+
+	var milestonePubKey iotago.MilestonePublicKey
+	copy(milestonePubKey[:], pubKey)
+
+	return coordinator.NewInMemoryEd25519MilestoneSignerProvider([]ed25519.PrivateKey{privKey}, keyManager, 1), milestonePubKey
+}
+
+// newRotationTestCoordinator creates a test coordinator with the given signer provider, the node knows the given latest milestone index.
+func newRotationTestCoordinator(t *testing.T, signerProvider coordinator.MilestoneSignerProvider, latestMilestoneIndex iotago.MilestoneIndex) (*coordinator.Coordinator, error) {
+	t.Helper()
+
+	return coordinator.New(
+		testMerkleRoots,
+		func() bool { return true },
+		func() *iotago.ProtocolParameters { return testProtoParams },
+		signerProvider,
+		nil,
+		nil,
+		(&testBlockSender{}).sendBlock,
+		coordinator.WithStateFilePath(filepath.Join(t.TempDir(), "coordinator.state")),
+		coordinator.WithLatestMilestoneIndexFunc(func() iotago.MilestoneIndex { return latestMilestoneIndex }),
+	)
+}
+
+func TestRotatingSignerProvider(t *testing.T) {
+	oldProvider, oldPubKey := testRotationSignerProvider(t, 0, 2)
+	newProvider, newPubKey := testRotationSignerProvider(t, 3, 0)
+
+	rotatingProvider, err := coordinator.NewRotatingSignerProvider(
+		coordinator.SigningKeyRotation{StartIndex: 3, Provider: newProvider},
+		coordinator.SigningKeyRotation{StartIndex: 1, Provider: oldProvider},
+	)
+	require.NoError(t, err)
+
+	rotations := rotatingProvider.Rotations()
+	require.Len(t, rotations, 2)
+	require.EqualValues(t, 1, rotations[0].StartIndex)
+	require.EqualValues(t, 3, rotations[1].StartIndex)
+
+	rotation, exists := rotatingProvider.ActiveRotation(2)
+	require.True(t, exists)
+	require.EqualValues(t, 1, rotation.StartIndex)
+
+	_, exists = rotatingProvider.ActiveRotation(0)
+	require.False(t, exists)
+	require.Empty(t, rotatingProvider.MilestoneIndexSigner(0).PublicKeys())
+
+	coo, sender := newBootstrappedTestCoordinator(t, nil, coordinator.WithSignerProvider(rotatingProvider))
+	require.Equal(t, []iotago.MilestonePublicKey{oldPubKey}, coo.ActiveSigningKeys())
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestonePublicKey{newPubKey}, coo.ActiveSigningKeys())
+
+	_, err = coo.IssueMilestone(nil)
+	require.NoError(t, err)
+
+	// the milestones are signed with the keys of the active rotation
+	blocks := sender.sentBlocks()
+	require.Len(t, blocks, 3)
+	for _, block := range blocks {
+		milestonePayload, ok := block.Payload.(*iotago.Milestone)
+		require.True(t, ok)
+
+		expectedPubKey := oldPubKey
+		if milestonePayload.Index >= 3 {
+			expectedPubKey = newPubKey
+		}
+		require.NoError(t, milestonePayload.VerifySignatures(1, iotago.MilestonePublicKeySet{expectedPubKey: struct{}{}}))
+	}
+}
+
+func TestRotatingSignerProviderInvalidSchedule(t *testing.T) {
+	provider, _ := testRotationSignerProvider(t, 0, 0)
+
+	_, err := coordinator.NewRotatingSignerProvider()
+	require.Error(t, err)
+
+	_, err = coordinator.NewRotatingSignerProvider(coordinator.SigningKeyRotation{StartIndex: 1})
+	require.Error(t, err)
+
+	_, err = coordinator.NewRotatingSignerProvider(
+		coordinator.SigningKeyRotation{StartIndex: 1, Provider: provider},
+		coordinator.SigningKeyRotation{StartIndex: 1, Provider: provider},
+	)
+	require.Error(t, err)
+
+	otherKeyManager := keymanager.New()
+	_, err = coordinator.NewRotatingSignerProvider(
+		coordinator.SigningKeyRotation{StartIndex: 1, Provider: provider},
+		coordinator.SigningKeyRotation{StartIndex: 5, Provider: coordinator.NewInMemoryEd25519MilestoneSignerProvider(nil, otherKeyManager, 2)},
+	)
+	require.Error(t, err)
+}
+
+func TestRotatingSignerProviderScheduleGap(t *testing.T) {
+	oldProvider, _ := testRotationSignerProvider(t, 0, 0)
+	// the key of the new rotation only becomes valid after the rotation started
+	newProvider, _ := testRotationSignerProvider(t, 15, 0)
+
+	rotatingProvider, err := coordinator.NewRotatingSignerProvider(
+		coordinator.SigningKeyRotation{StartIndex: 5, Provider: oldProvider},
+		coordinator.SigningKeyRotation{StartIndex: 10, Provider: newProvider},
+	)
+	require.NoError(t, err)
+
+	// the schedule doesn't cover the next milestone
+	_, err = newRotationTestCoordinator(t, rotatingProvider, 3)
+	require.ErrorIs(t, err, coordinator.ErrSigningKeyScheduleGap)
+
+	// the next rotation has no valid keys at its start
+	_, err = newRotationTestCoordinator(t, rotatingProvider, 5)
+	require.ErrorIs(t, err, coordinator.ErrSigningKeyScheduleGap)
+
+	// the gap is in the past
+	_, err = newRotationTestCoordinator(t, rotatingProvider, 15)
+	require.NoError(t, err)
+}
@@ -13,6 +13,8 @@ type State struct {
 	LatestMilestoneBlockID iotago.BlockID
 	LatestMilestoneID      iotago.MilestoneID
 	LatestMilestoneTime    time.Time
+	// whether the network was initialized for bootstrapping, but the first milestone was not issued yet.
+	PendingBootstrap bool
 }
 
 // jsoncoostate is the JSON representation of a coordinator state.
@@ -21,6 +23,7 @@ type jsoncoostate struct {
 	LatestMilestoneBlockID string `json:"latestMilestoneBlockId"`
 	LatestMilestoneID      string `json:"latestMilestoneId"`
 	LatestMilestoneTime    int64  `json:"latestMilestoneTime"`
+	PendingBootstrap       bool   `json:"pendingBootstrap,omitempty"`
 }
 
 func (cs *State) MarshalJSON() ([]byte, error) {
@@ -29,6 +32,7 @@ func (cs *State) MarshalJSON() ([]byte, error) {
 		LatestMilestoneBlockID: cs.LatestMilestoneBlockID.ToHex(),
 		LatestMilestoneID:      cs.LatestMilestoneID.ToHex(),
 		LatestMilestoneTime:    cs.LatestMilestoneTime.UnixNano(),
+		PendingBootstrap:       cs.PendingBootstrap,
 	})
 }
 
@@ -53,6 +57,7 @@ func (cs *State) UnmarshalJSON(data []byte) error {
 
 	cs.LatestMilestoneIndex = jsonCooState.LatestMilestoneIndex
 	cs.LatestMilestoneTime = time.Unix(0, jsonCooState.LatestMilestoneTime)
+	cs.PendingBootstrap = jsonCooState.PendingBootstrap
 
 	return nil
 }
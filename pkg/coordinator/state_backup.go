@@ -0,0 +1,50 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+)
+
+// stateBackupFilePath returns the path of the state backup with the given number, 1 is the most recent one.
+func (coo *Coordinator) stateBackupFilePath(number int) string {
+	return fmt.Sprintf("%s.%d", coo.opts.stateFilePath, number)
+}
+
+// rotateStateBackups keeps the state that was valid before the latest milestone as the most recent backup
+// and shifts the older backups, the oldest one is dropped.
+// Every step replaces a single file by an atomic rename, so a crash during the rotation loses at most the oldest backup.
+func (coo *Coordinator) rotateStateBackups() error {
+	if coo.opts.stateBackups <= 0 {
+		return nil
+	}
+
+	// the old state file contains the state before the latest milestone
+	previousState, err := os.ReadFile(coo.oldStateFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			// there was no previous state, e.g. the first milestone after bootstrapping
+			return nil
+		}
+
+		return fmt.Errorf("unable to read old coordinator state file: %w", err)
+	}
+
+	// shift the backups starting with the oldest one, the rename replaces the next older backup
+	for number := coo.opts.stateBackups - 1; number >= 1; number-- {
+		if err := os.Rename(coo.stateBackupFilePath(number), coo.stateBackupFilePath(number+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to rotate coordinator state backup %d: %w", number, err)
+		}
+	}
+
+	// write a temporary file first, so there is never a partially written backup
+	tmpFilePath := fmt.Sprintf("%s.tmp", coo.stateBackupFilePath(1))
+	if err := os.WriteFile(tmpFilePath, previousState, 0660); err != nil {
+		return fmt.Errorf("unable to write coordinator state backup: %w", err)
+	}
+
+	if err := os.Rename(tmpFilePath, coo.stateBackupFilePath(1)); err != nil {
+		return fmt.Errorf("unable to write coordinator state backup: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package coordinator
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// the name of the tracer used for the spans of the coordinator.
+	tracerName = "github.com/iotaledger/inx-coordinator/pkg/coordinator"
+
+	spanNameIssueMilestone = "coordinator.IssueMilestone"
+	spanNameMerkle         = "coordinator.ComputeMerkleRoots"
+	spanNameQuorum         = "coordinator.CheckQuorum"
+	spanNameSigning        = "coordinator.SignMilestone"
+	spanNameSend           = "coordinator.SendMilestone"
+)
+
+// startMilestoneSpan starts a span for a phase of the milestone issuance with the milestone index and the parents count as attributes.
+// If no TracerProvider was configured, the span is a no-op.
+func (coo *Coordinator) startMilestoneSpan(ctx context.Context, name string, index iotago.MilestoneIndex, parentsCount int) (context.Context, trace.Span) {
+	return coo.tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.Int64("milestone.index", int64(index)),
+		attribute.Int("milestone.parents_count", parentsCount),
+	))
+}
+
+// endSpan records the error on the span if it is not nil and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
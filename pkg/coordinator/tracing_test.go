@@ -0,0 +1,61 @@
+package coordinator_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestTracerProvider(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	sender := &testBlockSender{}
+	failSending := false
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if failSending {
+			return iotago.EmptyBlockID(), errors.New("node not reachable")
+		}
+
+		return sender.sendBlock(block, msIndex...)
+	}
+
+	coo, _ := newBootstrappedTestCoordinator(t, sendBlock, coordinator.WithTracerProvider(tracerProvider))
+
+	spans := spanRecorder.Ended()
+	spanNames := make([]string, 0, len(spans))
+	for _, span := range spans {
+		spanNames = append(spanNames, span.Name())
+		require.Contains(t, span.Attributes(), attribute.Int64("milestone.index", 1))
+		require.Contains(t, span.Attributes(), attribute.Int("milestone.parents_count", 1))
+	}
+	require.Equal(t, []string{
+		"coordinator.ComputeMerkleRoots",
+		"coordinator.SignMilestone",
+		"coordinator.SendMilestone",
+		"coordinator.IssueMilestone",
+	}, spanNames)
+
+	// the phases are children of the milestone issuance
+	issueSpan := spans[len(spans)-1]
+	for _, span := range spans[:len(spans)-1] {
+		require.Equal(t, issueSpan.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	// errors are recorded on the spans
+	failSending = true
+	_, err := coo.IssueMilestone(nil)
+	require.Error(t, err)
+
+	spans = spanRecorder.Ended()
+	require.Equal(t, codes.Error, spans[len(spans)-2].Status().Code)
+	require.Equal(t, codes.Error, spans[len(spans)-1].Status().Code)
+}
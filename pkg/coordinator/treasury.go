@@ -0,0 +1,47 @@
+package coordinator
+
+import (
+	"fmt"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// checkExpectedTreasuryOutput checks that the unspent treasury output was created by the latest issued receipt.
+// Otherwise the treasury output is stale, e.g. if the node didn't apply the previous milestone yet,
+// and the next receipt would spend funds that were already migrated.
+// The expected treasury output is cleared once it was confirmed. The caller must hold the milestoneLock.
+func (coo *Coordinator) checkExpectedTreasuryOutput(treasuryOutput *LatestTreasuryOutput) error {
+	expected := coo.expectedTreasuryOutput
+	if expected == nil {
+		return nil
+	}
+
+	if treasuryOutput.MilestoneID != expected.MilestoneID || treasuryOutput.Amount != expected.Amount {
+		return fmt.Errorf("%w: expected output of milestone %s with %d, got output of milestone %s with %d",
+			ErrTreasuryOutputStale,
+			iotago.EncodeHex(expected.MilestoneID[:]), expected.Amount,
+			iotago.EncodeHex(treasuryOutput.MilestoneID[:]), treasuryOutput.Amount,
+		)
+	}
+
+	coo.expectedTreasuryOutput = nil
+
+	return nil
+}
+
+// embedTreasuryTransaction embeds the transaction spending the given treasury output into the receipt.
+// Returns an error if the migrated funds of the receipt exceed the treasury.
+func embedTreasuryTransaction(receipt *iotago.ReceiptMilestoneOpt, treasuryOutput *LatestTreasuryOutput) error {
+	receiptSum := receipt.Sum()
+	if receiptSum > treasuryOutput.Amount {
+		return fmt.Errorf("migrated funds of the receipt exceed the treasury, receipt: %d, treasury: %d", receiptSum, treasuryOutput.Amount)
+	}
+
+	input := &iotago.TreasuryInput{}
+	copy(input[:], treasuryOutput.MilestoneID[:])
+	output := &iotago.TreasuryOutput{Amount: treasuryOutput.Amount - receiptSum}
+	receipt.Transaction = &iotago.TreasuryTransaction{Input: input, Output: output}
+	receipt.SortFunds()
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package coordinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestEmbedTreasuryTransaction(t *testing.T) {
+	treasuryOutput := &LatestTreasuryOutput{MilestoneID: iotago.MilestoneID{1}, Amount: 1000}
+
+	receipt := &iotago.ReceiptMilestoneOpt{Funds: iotago.MigratedFundsEntries{{Address: &iotago.Ed25519Address{1}, Deposit: 600}, {Address: &iotago.Ed25519Address{2}, Deposit: 400}}}
+	require.NoError(t, embedTreasuryTransaction(receipt, treasuryOutput))
+
+	treasuryTx := receipt.Transaction
+	require.NotNil(t, treasuryTx)
+	require.Equal(t, iotago.TreasuryInput(treasuryOutput.MilestoneID), *treasuryTx.Input)
+	require.Zero(t, treasuryTx.Output.Amount)
+
+	// the receipt exceeds the treasury
+	receipt = &iotago.ReceiptMilestoneOpt{Funds: iotago.MigratedFundsEntries{{Address: &iotago.Ed25519Address{1}, Deposit: 600}, {Address: &iotago.Ed25519Address{2}, Deposit: 401}}}
+	err := embedTreasuryTransaction(receipt, treasuryOutput)
+	require.ErrorContains(t, err, "receipt: 1001, treasury: 1000")
+	require.Nil(t, receipt.Transaction)
+}
@@ -0,0 +1,218 @@
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/logger"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	defaultWebhookQueueSize    = 100
+	defaultWebhookRetryAmount  = 3
+	defaultWebhookRetryBackoff = time.Second
+	defaultWebhookTimeout      = 5 * time.Second
+)
+
+// WebhookPayload is the JSON payload posted to the webhook for every issued milestone.
+type WebhookPayload struct {
+	// the index of the issued milestone.
+	Index iotago.MilestoneIndex `json:"index"`
+	// the hex encoded ID of the issued milestone.
+	MilestoneID string `json:"milestoneId"`
+	// the hex encoded ID of the block containing the milestone.
+	BlockID string `json:"blockId"`
+	// the timestamp of the milestone in unix seconds.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// WebhookNotifierOption is a function setting a WebhookNotifier option.
+type WebhookNotifierOption func(opts *webhookNotifierOptions)
+
+// webhookNotifierOptions define options for the WebhookNotifier.
+type webhookNotifierOptions struct {
+	// the logger used to log events.
+	logger *logger.Logger
+	// additional headers of the webhook requests.
+	headers map[string]string
+	// the amount of times to retry a failed webhook request.
+	retryAmount int
+	// the time to wait between retries of a failed webhook request.
+	retryBackoff time.Duration
+	// the timeout of a single webhook request.
+	timeout time.Duration
+	// the amount of notifications that are queued before new ones are dropped.
+	queueSize int
+}
+
+// WithWebhookLogger enables logging within the WebhookNotifier.
+func WithWebhookLogger(logger *logger.Logger) WebhookNotifierOption {
+	return func(opts *webhookNotifierOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithWebhookHeaders defines additional headers of the webhook requests, e.g. for authorization.
+func WithWebhookHeaders(headers map[string]string) WebhookNotifierOption {
+	return func(opts *webhookNotifierOptions) {
+		opts.headers = headers
+	}
+}
+
+// WithWebhookRetry defines how often a failed webhook request is retried and the time to wait between retries.
+func WithWebhookRetry(retryAmount int, retryBackoff time.Duration) WebhookNotifierOption {
+	return func(opts *webhookNotifierOptions) {
+		opts.retryAmount = retryAmount
+		opts.retryBackoff = retryBackoff
+	}
+}
+
+// WithWebhookTimeout defines the timeout of a single webhook request.
+func WithWebhookTimeout(timeout time.Duration) WebhookNotifierOption {
+	return func(opts *webhookNotifierOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// WithWebhookQueueSize defines the amount of notifications that are queued before new ones are dropped.
+func WithWebhookQueueSize(queueSize int) WebhookNotifierOption {
+	return func(opts *webhookNotifierOptions) {
+		opts.queueSize = queueSize
+	}
+}
+
+// WebhookNotifier posts a WebhookPayload to a URL for every milestone issued by the coordinator.
+// The notifications are queued and sent by Run, so the milestone issuance is never blocked.
+type WebhookNotifier struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	// the URL the notifications are posted to.
+	url string
+	// holds the notifier options.
+	opts *webhookNotifierOptions
+	// the client used to send the webhook requests.
+	client *http.Client
+	// the queued notifications.
+	queue chan *WebhookPayload
+	// the closure attached to the IssuedMilestone event of the coordinator.
+	onIssuedMilestone *events.Closure
+}
+
+// NewWebhookNotifier creates a new WebhookNotifier posting to the given URL.
+func NewWebhookNotifier(url string, opts ...WebhookNotifierOption) *WebhookNotifier {
+	options := &webhookNotifierOptions{
+		retryAmount:  defaultWebhookRetryAmount,
+		retryBackoff: defaultWebhookRetryBackoff,
+		timeout:      defaultWebhookTimeout,
+		queueSize:    defaultWebhookQueueSize,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &WebhookNotifier{
+		WrappedLogger: logger.NewWrappedLogger(options.logger),
+		url:           url,
+		opts:          options,
+		client:        &http.Client{Timeout: options.timeout},
+		queue:         make(chan *WebhookPayload, options.queueSize),
+	}
+}
+
+// Attach subscribes the notifier to the issued milestones of the coordinator.
+func (n *WebhookNotifier) Attach(coo *Coordinator) {
+	n.onIssuedMilestone = events.NewClosure(func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, blockID iotago.BlockID) {
+		// the event is triggered while the coordinator holds its lock, so the state can be read directly
+		n.enqueue(&WebhookPayload{
+			Index:       index,
+			MilestoneID: iotago.EncodeHex(milestoneID[:]),
+			BlockID:     blockID.ToHex(),
+			Timestamp:   coo.State().LatestMilestoneTime.Unix(),
+		})
+	})
+	coo.Events.IssuedMilestone.Hook(n.onIssuedMilestone)
+}
+
+// Detach unsubscribes the notifier from the issued milestones of the coordinator.
+func (n *WebhookNotifier) Detach(coo *Coordinator) {
+	if n.onIssuedMilestone == nil {
+		return
+	}
+	coo.Events.IssuedMilestone.Detach(n.onIssuedMilestone)
+}
+
+// enqueue queues the notification without blocking, it is dropped if the queue is full.
+func (n *WebhookNotifier) enqueue(payload *WebhookPayload) {
+	select {
+	case n.queue <- payload:
+	default:
+		n.LogWarnf("webhook queue full, dropping notification for milestone %d", payload.Index)
+	}
+}
+
+// Run sends the queued notifications until the context is done.
+func (n *WebhookNotifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case payload := <-n.queue:
+			if err := n.notify(ctx, payload); err != nil {
+				n.LogWarnf("webhook notification for milestone %d failed: %s", payload.Index, err)
+			}
+		}
+	}
+}
+
+// notify posts the payload to the webhook and retries failed requests.
+func (n *WebhookNotifier) notify(ctx context.Context, payload *WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	for i := 0; ; i++ {
+		err = n.post(ctx, body)
+		if err == nil || i >= n.opts.retryAmount {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(n.opts.retryBackoff):
+		}
+	}
+}
+
+// post sends a single webhook request.
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.opts.headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package coordinator_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// webhookRequest holds the observations of a request received by the test webhook server.
+type webhookRequest struct {
+	authorization string
+	payload       *coordinator.WebhookPayload
+	decodeErr     error
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var requestsCount atomic.Int32
+	requests := make(chan *webhookRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &webhookRequest{authorization: r.Header.Get("Authorization")}
+
+		// the first request fails and is retried
+		if requestsCount.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			requests <- request
+
+			return
+		}
+
+		request.payload = &coordinator.WebhookPayload{}
+		request.decodeErr = json.NewDecoder(r.Body).Decode(request.payload)
+		requests <- request
+	}))
+	defer server.Close()
+
+	coo, _ := newTestCoordinator(t, nil)
+
+	notifier := coordinator.NewWebhookNotifier(server.URL,
+		coordinator.WithWebhookHeaders(map[string]string{"Authorization": "secret"}),
+		coordinator.WithWebhookRetry(1, time.Millisecond),
+	)
+	notifier.Attach(coo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Run(ctx)
+
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+	blockID, err := coo.Bootstrap()
+	require.NoError(t, err)
+
+	// assertions are made in the test goroutine, the handler only records the requests
+	receiveRequest := func() *webhookRequest {
+		select {
+		case request := <-requests:
+			require.Equal(t, "secret", request.authorization)
+
+			return request
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "webhook was not notified")
+
+			return nil
+		}
+	}
+
+	// the failed request doesn't carry a decoded payload
+	require.Nil(t, receiveRequest().payload)
+
+	request := receiveRequest()
+	require.NoError(t, request.decodeErr)
+	state := coo.State()
+	require.EqualValues(t, 1, request.payload.Index)
+	require.Equal(t, iotago.EncodeHex(state.LatestMilestoneID[:]), request.payload.MilestoneID)
+	require.Equal(t, blockID.ToHex(), request.payload.BlockID)
+	require.Equal(t, state.LatestMilestoneTime.Unix(), request.payload.Timestamp)
+	require.EqualValues(t, 2, requestsCount.Load())
+}
@@ -5,6 +5,7 @@ const (
 	PriorityStopTangleListener
 	PriorityStopTreasuryListener
 	PriorityStopMigrator
+	PriorityStopWebhookNotifier
 	PriorityStopCoordinator
 	PriorityStopCoordinatorMilestoneTicker
 )